@@ -4,22 +4,36 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"path/filepath"
+	"strings"
 
 	"github.com/fatih/color"
-	"github.com/reakaleek/gh-action-readme/internal/action"
+	"github.com/reakaleek/gh-action-readme/internal/config"
 	"github.com/reakaleek/gh-action-readme/internal/helpers"
 	"github.com/reakaleek/gh-action-readme/internal/markdown"
+	"github.com/reakaleek/gh-action-readme/internal/report"
 	"github.com/urfave/cli/v2"
 )
 
+// options are the flags of the diff command
+type options struct {
+	readmePath   string
+	workflowPath string
+	recursive    bool
+	validate     bool
+	format       string
+	output       string
+	// context is the number of unchanged lines around the changes
+	context int
+	// patchPath is the file the combined patch of all READMEs is written to
+	patchPath string
+}
+
 func NewCommand() *cli.Command {
-	var readmePath string
-	var recursive bool
+	var opts options
 	return &cli.Command{
 		Name:  "diff",
 		Usage: "Diff README.md",
-		Flags: []cli.Flag{
+		Flags: append([]cli.Flag{
 			&cli.StringFlag{
 				Name:   "action",
 				Hidden: true,
@@ -27,169 +41,284 @@ func NewCommand() *cli.Command {
 			},
 			&cli.StringFlag{
 				Name:        "readme",
-				Value:       "README.md",
-				Destination: &readmePath,
+				Destination: &opts.readmePath,
+				Usage:       "README filename, defaults to the readme of " + config.FileName + " or README.md",
+			},
+			&cli.StringFlag{
+				Name:        "workflow",
+				Destination: &opts.workflowPath,
+				Usage:       "Document a reusable workflow (on: workflow_call) instead of action.yml/action.yaml",
 			},
 			&cli.BoolFlag{
 				Name:        "recursive",
 				Aliases:     []string{"r"},
 				Value:       false,
-				Destination: &recursive,
+				Destination: &opts.recursive,
 				Usage:       "Search recursively for all action.yml/action.yaml files",
 			},
-		},
+			&cli.BoolFlag{
+				Name:        "validate",
+				Value:       false,
+				Destination: &opts.validate,
+				Usage:       "Validate action.yml/action.yaml against the metadata syntax before generating the documentation",
+			},
+			&cli.IntFlag{
+				Name:        "unified",
+				Aliases:     []string{"U"},
+				Value:       markdown.DefaultContext,
+				Destination: &opts.context,
+				Usage:       "Number of unchanged lines shown around the changes",
+				Action: func(_ *cli.Context, value int) error {
+					if value < 0 {
+						return fmt.Errorf("invalid context %d, expected a number of lines >= 0", value)
+					}
+					return nil
+				},
+			},
+			&cli.StringFlag{
+				Name:        "patch",
+				Destination: &opts.patchPath,
+				Usage:       "Write one patch of all READMEs to the given file, apply it with git apply",
+			},
+		}, report.Flags(&opts.format, &opts.output, report.FormatGitHub)...),
 		Action: func(ctx *cli.Context) error {
-			return diffRun(readmePath, recursive)
+			return diffRun(opts)
 		},
 	}
 }
 
-func diffRun(readmePath string, recursive bool) error {
-	if recursive {
-		return diffRunRecursive(readmePath)
+func diffRun(opts options) error {
+	if opts.recursive {
+		return diffRunRecursive(opts)
 	}
-	return diffRunSingle(readmePath)
+	return diffRunSingle(opts)
 }
 
-func diffRunRecursive(readmeFilename string) error {
-	actionFiles, err := helpers.FindAllActionFiles(".")
+func diffRunRecursive(opts options) error {
+	found, err := config.Discover(opts.readmePath)
 	if err != nil {
 		return err
 	}
 	
-	if len(actionFiles) == 0 {
+	if len(found.Actions) == 0 && len(found.Workflows) == 0 {
 		return fmt.Errorf("no action.yml or action.yaml files found")
 	}
 	
-	helpers.PrintHeader("Found %d action file(s)\n\n", len(actionFiles))
+	text := opts.format == report.FormatText
+	if text {
+		helpers.PrintHeader("Found %d action file(s)\n\n", len(found.Actions))
+		if len(found.Workflows) > 0 {
+			helpers.PrintHeader("Found %d documented reusable workflow(s)\n\n", len(found.Workflows))
+		}
+	}
+	
+	if opts.validate {
+		if err := validateActionFiles(found.ActionFiles()); err != nil {
+			return err
+		}
+	}
 	
-	hasAnyDiff := false
-	upToDate := 0
-	outOfDate := 0
+	r := report.New("diff")
 	
-	for _, actionPath := range actionFiles {
-		dir := filepath.Dir(actionPath)
-		readmePath := filepath.Join(dir, readmeFilename)
+	for _, t := range found.Targets() {
+		actionPath, readmePath := t.Action, t.Readme
 		
-		hasDiff, _, err := diffSingleActionWithOutput(actionPath, readmePath)
+		result, err := diffReadme(actionPath, readmePath, t.Config.Settings(), opts.context)
 		if err != nil {
-			return fmt.Errorf("error diffing %s: %w", readmePath, err)
+			if text {
+				return fmt.Errorf("error diffing %s: %w", readmePath, err)
+			}
+			// The JSON report has an entry for every README, so the other READMEs are still diffed
+			r.Failed(actionPath, readmePath, err)
+			continue
 		}
+		r.Add(result.entry(actionPath, readmePath))
 		
-		if hasDiff {
-			hasAnyDiff = true
-			outOfDate++
+		if !text {
+			continue
+		}
+		helpers.PrintUsageIssues(result.issues)
+		if result.diff.HasDiff {
+			red := color.New(color.FgRed).SprintFunc()
+			fmt.Printf("%s %s\n", red("✗"), readmePath)
+			printStaleSections(result.stale)
+			fmt.Println()
+			fmt.Println(result.diff.PrettyDiff)
+			fmt.Println()
 		} else {
 			green := color.New(color.FgGreen).SprintFunc()
 			fmt.Printf("%s %s\n", green("✓"), readmePath)
-			upToDate++
 		}
 	}
 	
-	helpers.PrintSummary(upToDate, "up-to-date", color.FgGreen, outOfDate, "out-of-date", color.FgRed)
+	if text {
+		helpers.PrintSummary(r.Summary.UpToDate, "up-to-date", color.FgGreen, r.Summary.OutOfDate, "out-of-date", color.FgRed)
+	}
+	if err := emit(r, opts); err != nil {
+		return err
+	}
 	
-	if hasAnyDiff {
+	if r.Summary.OutOfDate > 0 || r.Summary.Error > 0 {
 		return cli.Exit("", 1)
 	}
 	
 	return nil
 }
 
-func diffSingleActionWithOutput(actionPath, readmePath string) (hasDiff bool, fileExists bool, err error) {
-	actionParser := action.NewParser()
-	a, err := actionParser.Parse(actionPath)
+// readmeDiff is the difference between a README and the README generated for its action
+type readmeDiff struct {
+	diff markdown.DiffResult
+	// exists is false if the README doesn't exist yet
+	exists bool
+	stale  []markdown.StaleSection
+	// line is the first line of the README that differs
+	line   int
+	issues []markdown.UsageIssue
+}
+
+// diffReadme compares the README with the README that update would write, without writing it
+func diffReadme(actionPath, readmePath string, settings markdown.Settings, context int) (readmeDiff, error) {
+	a, err := helpers.ParseActionFile(actionPath)
 	if err != nil {
-		return false, false, err
+		return readmeDiff{}, err
 	}
 	
-	var doc *markdown.Doc
-	fileExists = true
-	doc, err = markdown.NewDoc(readmePath)
+	result := readmeDiff{exists: true}
+	doc, err := markdown.NewDoc(readmePath)
 	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			// File doesn't exist, create an empty doc to compare against
-			fileExists = false
-			doc = markdown.NewEmptyDoc(readmePath)
-		} else {
-			return false, false, err
+		if !errors.Is(err, os.ErrNotExist) {
+			return readmeDiff{}, err
 		}
+		// File doesn't exist, create an empty doc to compare against
+		result.exists = false
+		doc = markdown.NewEmptyDoc(readmePath)
 	}
-	
+	doc.Configure(settings)
+	result.issues = doc.LintUsage(a)
+
 	// Create what the file should be
 	expectedDoc, err := markdown.NewDocOrCreate(readmePath)
 	if err != nil {
-		return false, false, err
+		return readmeDiff{}, err
 	}
-	err = expectedDoc.Update(&a)
+	expectedDoc.Configure(settings)
+	err = expectedDoc.Update(a)
 	if err != nil {
-		return false, false, err
+		return readmeDiff{}, err
 	}
 	
 	// Compare current state with expected state
-	diff := doc.Diff(expectedDoc)
-	if diff.HasDiff {
-		red := color.New(color.FgRed).SprintFunc()
-		fmt.Printf("%s %s\n\n", red("✗"), readmePath)
-		fmt.Println(diff.PrettyDiff)
-		fmt.Println()
-		return true, fileExists, nil
-	}
-	return false, fileExists, nil
+	result.diff = doc.DiffContext(expectedDoc, context)
+	if result.diff.HasDiff {
+		result.stale = doc.StaleSections(expectedDoc, a)
+		result.line = doc.FirstChangedLine(expectedDoc)
+	}
+	return result, nil
 }
 
-func diffSingleAction(actionPath, readmePath string) (bool, error) {
-	actionParser := action.NewParser()
-	a, err := actionParser.Parse(actionPath)
-	if err != nil {
-		return false, err
+// entry returns the report entry of the README
+func (d readmeDiff) entry(actionPath, readmePath string) report.Entry {
+	status := report.StatusUpToDate
+	if d.diff.HasDiff {
+		status = report.StatusOutOfDate
 	}
-	
-	var doc *markdown.Doc
-	doc, err = markdown.NewDoc(readmePath)
+	return report.Entry{
+		Action:        actionPath,
+		Readme:        readmePath,
+		Status:        status,
+		Line:          d.line,
+		StaleSections: report.StaleSections(d.stale),
+		Patch:         d.diff.Patch,
+		UsageIssues:   d.issues,
+	}
+}
+
+func diffRunSingle(opts options) error {
+	cfg, err := config.LoadWithReadme(".", opts.readmePath)
 	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			// File doesn't exist, create an empty doc to compare against
-			doc = markdown.NewEmptyDoc(readmePath)
-		} else {
-			return false, err
+		return err
+	}
+	readmePath := cfg.ReadmeFilename(".")
+	actionPath := opts.workflowPath
+	if actionPath == "" {
+		actionPath, err = config.FindActionFileOrReadme(readmePath)
+		if err != nil {
+			return err
 		}
 	}
 	
-	// Create what the file should be
-	expectedDoc, err := markdown.NewDocOrCreate(readmePath)
-	if err != nil {
-		return false, err
+	if opts.validate && actionPath != "" {
+		if err := validateActionFiles([]string{actionPath}); err != nil {
+			return err
+		}
 	}
-	err = expectedDoc.Update(&a)
+
+	text := opts.format == report.FormatText
+	r := report.New("diff")
+	result, err := diffReadme(actionPath, readmePath, cfg.Settings(), opts.context)
 	if err != nil {
-		return false, err
+		if text {
+			return err
+		}
+		r.Failed(actionPath, readmePath, err)
+		if err := emit(r, opts); err != nil {
+			return err
+		}
+		return cli.Exit("", 1)
+	}
+	r.Add(result.entry(actionPath, readmePath))
+	if err := emit(r, opts); err != nil {
+		return err
 	}
 	
-	// Compare current state with expected state
-	diff := doc.Diff(expectedDoc)
-	if diff.HasDiff {
-		fmt.Printf("\n%s\n\n", readmePath)
-		fmt.Println(diff.PrettyDiff)
-		return true, nil
+	if text {
+		helpers.PrintUsageIssues(result.issues)
+		if result.diff.HasDiff {
+			fmt.Printf("\n%s\n", readmePath)
+			printStaleSections(result.stale)
+			fmt.Println()
+			fmt.Println(result.diff.PrettyDiff)
+		}
 	}
-	return false, nil
+	if result.diff.HasDiff {
+		if !text {
+			return cli.Exit("", 1)
+		}
+		return cli.Exit(fmt.Sprintf("%s is not up-to-date", readmePath), 1)
+	}
+	if text {
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s %s is up-to-date\n", green("✓"), readmePath)
+	}
+	return nil
 }
 
-func diffRunSingle(readmePath string) error {
-	actionPath, err := helpers.FindActionFile()
-	if err != nil {
-		return err
+// printStaleSections prints how each stale section differs
+// Example: "  inputs: 1 row changed (added `token`)"
+func printStaleSections(stale []markdown.StaleSection) {
+	for _, s := range stale {
+		fmt.Printf("  %s\n", s)
 	}
+}
 
-	hasDiff, err := diffSingleAction(actionPath, readmePath)
-	if err != nil {
+// emit writes the report and the combined patch of all READMEs
+func emit(r *report.Report, opts options) error {
+	if err := r.Emit(opts.format, opts.output); err != nil {
 		return err
 	}
-	
-	if hasDiff {
-		return cli.Exit(fmt.Sprintf("%s is not up-to-date", readmePath), 1)
+	if opts.patchPath == "" {
+		return nil
+	}
+	var patch strings.Builder
+	for _, e := range r.Actions {
+		patch.WriteString(e.Patch)
+	}
+	return os.WriteFile(opts.patchPath, []byte(patch.String()), 0644)
+}
+
+func validateActionFiles(actionFiles []string) error {
+	if err := helpers.ValidateActionFiles(actionFiles); err != nil {
+		return cli.Exit(err.Error(), 1)
 	}
-	green := color.New(color.FgGreen).SprintFunc()
-	fmt.Printf("%s %s is up-to-date\n", green("✓"), readmePath)
 	return nil
 }
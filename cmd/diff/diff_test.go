@@ -1,14 +1,19 @@
 package diff
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/reakaleek/gh-action-readme/internal/markdown"
+	"github.com/reakaleek/gh-action-readme/internal/report"
 	"github.com/stretchr/testify/assert"
+	"github.com/urfave/cli/v2"
 )
 
-func TestDiffSingleAction_MissingFile(t *testing.T) {
+func TestDiffReadme_MissingFile(t *testing.T) {
 	// Test that diff detects missing files as having a diff
 	// This requires creating a temp action.yml file
 	
@@ -25,18 +30,19 @@ description: Test Description`
 	// Don't create README.md
 	
 	// act
-	hasDiff, err := diffSingleAction(actionPath, readmePath)
+	result, err := diffReadme(actionPath, readmePath, markdown.Settings{}, markdown.DefaultContext)
 	
 	// assert
 	assert.NoError(t, err)
-	assert.True(t, hasDiff, "Missing README should show as having diff")
+	assert.True(t, result.diff.HasDiff, "Missing README should show as having diff")
+	assert.False(t, result.exists, "File should be marked as non-existent")
 	
 	// Verify README was not created
 	_, err = os.Stat(readmePath)
 	assert.True(t, os.IsNotExist(err), "diff should not create README file")
 }
 
-func TestDiffSingleAction_ExistingFileUpToDate(t *testing.T) {
+func TestDiffReadme_ExistingFileUpToDate(t *testing.T) {
 	// Test that diff correctly identifies up-to-date files
 	
 	tmpDir := t.TempDir()
@@ -79,14 +85,14 @@ steps:
 	assert.NoError(t, err)
 	
 	// act
-	hasDiff, err := diffSingleAction(actionPath, readmePath)
+	result, err := diffReadme(actionPath, readmePath, markdown.Settings{}, markdown.DefaultContext)
 	
 	// assert
 	assert.NoError(t, err)
-	assert.False(t, hasDiff, "Up-to-date README should not show diff")
+	assert.False(t, result.diff.HasDiff, "Up-to-date README should not show diff")
 }
 
-func TestDiffSingleAction_ExistingFileOutOfDate(t *testing.T) {
+func TestDiffReadme_ExistingFileOutOfDate(t *testing.T) {
 	// Test that diff correctly identifies out-of-date files
 	
 	tmpDir := t.TempDir()
@@ -108,37 +114,177 @@ Old Description
 	assert.NoError(t, err)
 	
 	// act
-	hasDiff, err := diffSingleAction(actionPath, readmePath)
+	result, err := diffReadme(actionPath, readmePath, markdown.Settings{}, markdown.DefaultContext)
 	
 	// assert
 	assert.NoError(t, err)
-	assert.True(t, hasDiff, "Out-of-date README should show diff")
+	assert.True(t, result.diff.HasDiff, "Out-of-date README should show diff")
 }
 
-func TestDiffSingleActionWithOutput_MissingFile(t *testing.T) {
-	// Test the recursive mode function
-	
+func TestDiffReadme_LoneEndMarker(t *testing.T) {
+	// Test that a README whose only placeholder is an end marker fails instead of being up-to-date
+
 	tmpDir := t.TempDir()
 	actionPath := filepath.Join(tmpDir, "action.yml")
 	readmePath := filepath.Join(tmpDir, "README.md")
-	
-	// Create action.yml
-	actionContent := `name: Test Action
-description: Test Description`
-	err := os.WriteFile(actionPath, []byte(actionContent), 0644)
+	err := os.WriteFile(actionPath, []byte("name: Test Action\ndescription: Test\n"), 0644)
 	assert.NoError(t, err)
-	
-	// Don't create README.md
-	
+	err = os.WriteFile(readmePath, []byte("# Test Action\n<!--/inputs-->\n"), 0644)
+	assert.NoError(t, err)
+
 	// act
-	hasDiff, fileExists, err := diffSingleActionWithOutput(actionPath, readmePath)
-	
+	_, err = diffReadme(actionPath, readmePath, markdown.Settings{}, markdown.DefaultContext)
+
 	// assert
+	assert.EqualError(t, err, readmePath+":2: <!--/inputs--> has no opening <!--inputs-->")
+}
+
+func TestDiffReadme_WithoutAction(t *testing.T) {
+	// Test that a README that only lists the actions in subdirectories can be diffed
+
+	tmpDir := t.TempDir()
+	readmePath := filepath.Join(tmpDir, "README.md")
+	err := os.MkdirAll(filepath.Join(tmpDir, "deploy"), 0755)
 	assert.NoError(t, err)
-	assert.True(t, hasDiff, "Missing README should show as having diff")
-	assert.False(t, fileExists, "File should be marked as non-existent")
-	
-	// Verify README was not created
-	_, err = os.Stat(readmePath)
-	assert.True(t, os.IsNotExist(err), "diff should not create README file")
+	err = os.WriteFile(filepath.Join(tmpDir, "deploy", "action.yml"), []byte("name: Deploy\n"), 0644)
+	assert.NoError(t, err)
+	err = os.WriteFile(readmePath, []byte("<!--actions-index-->\n<!--/actions-index-->\n"), 0644)
+	assert.NoError(t, err)
+
+	// act
+	result, err := diffReadme("", readmePath, markdown.Settings{}, markdown.DefaultContext)
+
+	// assert
+	assert.NoError(t, err)
+	assert.True(t, result.exists)
+	assert.True(t, result.diff.HasDiff, "README without the actions table should show as having diff")
+}
+
+func TestDiffReadme_IncludedFileChanged(t *testing.T) {
+	// Test that diff detects a README whose included example is out of date
+
+	tmpDir := t.TempDir()
+	actionPath := filepath.Join(tmpDir, "action.yml")
+	readmePath := filepath.Join(tmpDir, "README.md")
+	examplePath := filepath.Join(tmpDir, "example.yml")
+	err := os.WriteFile(actionPath, []byte("name: Test Action\n"), 0644)
+	assert.NoError(t, err)
+	err = os.WriteFile(examplePath, []byte("- uses: org/repo@v2\n"), 0644)
+	assert.NoError(t, err)
+	readme := "<!-- Generated by https://github.com/reakaleek/gh-action-readme -->\n" +
+		"<!--include file=\"example.yml\"-->\n```yaml\n- uses: org/repo@v1\n```\n<!--/include-->\n"
+	err = os.WriteFile(readmePath, []byte(readme), 0644)
+	assert.NoError(t, err)
+
+	// act
+	result, err := diffReadme(actionPath, readmePath, markdown.Settings{}, markdown.DefaultContext)
+
+	// assert
+	assert.NoError(t, err)
+	assert.True(t, result.diff.HasDiff, "README with an outdated include should show as having diff")
+}
+
+func TestDiffRecursive_JSONReport(t *testing.T) {
+	// Test that the JSON report lists every README with its status, stale sections and patch
+
+	tmpDir := t.TempDir()
+	for _, name := range []string{"build", "deploy"} {
+		err := os.MkdirAll(filepath.Join(tmpDir, name), 0755)
+		assert.NoError(t, err)
+		err = os.WriteFile(filepath.Join(tmpDir, name, "action.yml"), []byte("name: "+name+"\n"), 0644)
+		assert.NoError(t, err)
+	}
+	readme := "<!-- Generated by https://github.com/reakaleek/gh-action-readme -->\n# <!--name-->old<!--/name-->\n"
+	err := os.WriteFile(filepath.Join(tmpDir, "build", "README.md"), []byte(readme), 0644)
+	assert.NoError(t, err)
+	originalWd, _ := os.Getwd()
+	defer func() { _ = os.Chdir(originalWd) }()
+	err = os.Chdir(tmpDir)
+	assert.NoError(t, err)
+	app := &cli.App{
+		Commands:       []*cli.Command{NewCommand()},
+		ExitErrHandler: func(_ *cli.Context, _ error) {},
+	}
+
+	// act
+	err = app.Run([]string{"app", "diff", "--recursive", "--format", "json", "--output", "report.json"})
+
+	// assert
+	assert.Error(t, err, "out-of-date READMEs should fail the diff")
+	content, err := os.ReadFile("report.json")
+	assert.NoError(t, err)
+	var r report.Report
+	err = json.Unmarshal(content, &r)
+	assert.NoError(t, err)
+	assert.Equal(t, "diff", r.Command)
+	assert.Equal(t, report.Summary{Total: 2, OutOfDate: 2}, r.Summary)
+	assert.Equal(t, filepath.Join("build", "README.md"), r.Actions[0].Readme)
+	assert.Equal(t, report.StatusOutOfDate, r.Actions[0].Status)
+	assert.Equal(t, []report.Section{{Name: "name", Line: 2, Detail: `changed from "old" to "build"`}}, r.Actions[0].StaleSections)
+	assert.Contains(t, r.Actions[0].Patch, "-# <!--name-->old<!--/name-->\n+# <!--name-->build<!--/name-->\n")
+	assert.Equal(t, filepath.Join("deploy", "README.md"), r.Actions[1].Readme)
+	assert.Contains(t, r.Actions[1].Patch, "--- /dev/null\n+++ b/deploy/README.md\n")
+}
+
+func TestDiffRecursive_GitHubStepSummary(t *testing.T) {
+	// Test that the github format appends the status of every README to the job summary
+
+	tmpDir := t.TempDir()
+	err := os.MkdirAll(filepath.Join(tmpDir, "build"), 0755)
+	assert.NoError(t, err)
+	err = os.WriteFile(filepath.Join(tmpDir, "build", "action.yml"), []byte("name: build\n"), 0644)
+	assert.NoError(t, err)
+	summaryPath := filepath.Join(tmpDir, "summary.md")
+	t.Setenv("GITHUB_STEP_SUMMARY", summaryPath)
+	originalWd, _ := os.Getwd()
+	defer func() { _ = os.Chdir(originalWd) }()
+	err = os.Chdir(tmpDir)
+	assert.NoError(t, err)
+	app := &cli.App{
+		Commands:       []*cli.Command{NewCommand()},
+		ExitErrHandler: func(_ *cli.Context, _ error) {},
+	}
+
+	// act
+	err = app.Run([]string{"app", "diff", "--recursive", "--format", "github"})
+
+	// assert
+	assert.Error(t, err, "out-of-date READMEs should fail the diff")
+	content, err := os.ReadFile(summaryPath)
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "| `build/action.yml` | `build/README.md` | out-of-date | `name`: missing<br>`description`: missing<br>`inputs`: missing<br>`outputs`: missing<br>`usage`: missing |\n")
+}
+
+func TestDiffRecursive_Patch(t *testing.T) {
+	// Test that the patch of all READMEs is written to one file
+
+	tmpDir := t.TempDir()
+	for _, name := range []string{"build", "deploy"} {
+		err := os.MkdirAll(filepath.Join(tmpDir, name), 0755)
+		assert.NoError(t, err)
+		err = os.WriteFile(filepath.Join(tmpDir, name, "action.yml"), []byte("name: "+name+"\n"), 0644)
+		assert.NoError(t, err)
+	}
+	readme := "<!-- Generated by https://github.com/reakaleek/gh-action-readme -->\n# <!--name-->old<!--/name-->\n"
+	err := os.WriteFile(filepath.Join(tmpDir, "build", "README.md"), []byte(readme), 0644)
+	assert.NoError(t, err)
+	originalWd, _ := os.Getwd()
+	defer func() { _ = os.Chdir(originalWd) }()
+	err = os.Chdir(tmpDir)
+	assert.NoError(t, err)
+	app := &cli.App{
+		Commands:       []*cli.Command{NewCommand()},
+		ExitErrHandler: func(_ *cli.Context, _ error) {},
+	}
+
+	// act
+	err = app.Run([]string{"app", "diff", "--recursive", "-U", "0", "--patch", "readme.patch"})
+
+	// assert
+	assert.Error(t, err, "out-of-date READMEs should fail the diff")
+	content, err := os.ReadFile("readme.patch")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(content), "--- a/build/README.md\n+++ b/build/README.md\n"+
+		"@@ -2 +2 @@\n-# <!--name-->old<!--/name-->\n+# <!--name-->build<!--/name-->\n"+
+		"--- /dev/null\n+++ b/deploy/README.md\n@@ -0,0 +1,"), string(content))
 }
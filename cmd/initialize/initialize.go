@@ -2,12 +2,14 @@ package initialize
 
 import (
 	"embed"
+	"errors"
 	"fmt"
 	"os"
-	"path/filepath"
 
 	"github.com/fatih/color"
+	"github.com/reakaleek/gh-action-readme/internal/config"
 	"github.com/reakaleek/gh-action-readme/internal/helpers"
+	"github.com/reakaleek/gh-action-readme/internal/report"
 	"github.com/urfave/cli/v2"
 )
 
@@ -18,19 +20,21 @@ func NewCommand() *cli.Command {
 	var template string
 	var readmePath string
 	var recursive bool
+	var format string
+	var output string
 	return &cli.Command{
 		Name:  "init",
 		Usage: "Initialize README.md",
-		Flags: []cli.Flag{
+		Flags: append([]cli.Flag{
 			&cli.StringFlag{
 				Name:        "readme",
-				Value:       "README.md",
 				Destination: &readmePath,
+				Usage:       "README filename, defaults to the readme of " + config.FileName + " or README.md",
 			},
 			&cli.StringFlag{
 				Name:        "template",
-				Value:       "default",
 				Destination: &template,
+				Usage:       "The builtin default template or the path of a markdown file, defaults to the templates.init of " + config.FileName,
 			},
 			&cli.BoolFlag{
 				Name:        "recursive",
@@ -39,86 +43,143 @@ func NewCommand() *cli.Command {
 				Destination: &recursive,
 				Usage:       "Search recursively for all action.yml/action.yaml files and create README.md next to each",
 			},
-		},
+		}, report.Flags(&format, &output)...),
 		Action: func(ctx *cli.Context) error {
-			return initRun(template, readmePath, recursive)
+			return initRun(template, readmePath, recursive, format, output)
 		},
 	}
 }
 
-func initRun(template string, readmeFilename string, recursive bool) error {
+func initRun(template string, readmeFilename string, recursive bool, format string, output string) error {
 	if recursive {
-		return initRunRecursive(template, readmeFilename)
+		return initRunRecursive(template, readmeFilename, format, output)
 	}
-	return initRunSingle(template, readmeFilename)
+	return initRunSingle(template, readmeFilename, format, output)
 }
 
-func initRunRecursive(template string, readmeFilename string) error {
-	actionFiles, err := helpers.FindAllActionFiles(".")
+func initRunRecursive(template string, readmeFilename string, format string, output string) error {
+	found, err := config.Discover(readmeFilename)
 	if err != nil {
 		return err
 	}
 
-	if len(actionFiles) == 0 {
+	if len(found.Actions) == 0 {
 		return fmt.Errorf("no action.yml or action.yaml files found")
 	}
 
-	helpers.PrintHeader("Found %d action file(s)\n\n", len(actionFiles))
+	text := format == report.FormatText
+	if text {
+		helpers.PrintHeader("Found %d action file(s)\n\n", len(found.Actions))
+	}
+
+	r := report.New("init")
 
-	created := 0
-	skipped := 0
-	
 	green := color.New(color.FgGreen).SprintFunc()
 	yellow := color.New(color.FgYellow).SprintFunc()
 
-	for _, actionPath := range actionFiles {
-		dir := filepath.Dir(actionPath)
-		readmePath := filepath.Join(dir, readmeFilename)
+	for _, t := range found.Actions {
+		actionPath, readmePath := t.Action, t.Readme
 
 		// Check if README already exists
 		if _, err := os.Stat(readmePath); err == nil {
-			fmt.Printf("%s Skipped: %s (already exists)\n", yellow("○"), readmePath)
-			skipped++
+			r.Add(report.Entry{Action: actionPath, Readme: readmePath, Status: report.StatusSkipped})
+			if text {
+				fmt.Printf("%s Skipped: %s (already exists)\n", yellow("○"), readmePath)
+			}
 			continue
 		}
 
-		if err := createReadmeFromTemplate(template, readmePath); err != nil {
-			return fmt.Errorf("error creating %s: %w", readmePath, err)
+		if err := createReadmeFromTemplate(templateOf(template, t.Config), readmePath); err != nil {
+			if text {
+				return fmt.Errorf("error creating %s: %w", readmePath, err)
+			}
+			r.Failed(actionPath, readmePath, err)
+			continue
 		}
 
-		fmt.Printf("%s Created: %s\n", green("✓"), readmePath)
-		created++
+		r.Add(report.Entry{Action: actionPath, Readme: readmePath, Status: report.StatusCreated})
+		if text {
+			fmt.Printf("%s Created: %s\n", green("✓"), readmePath)
+		}
 	}
 
-	helpers.PrintSummary(created, "created", color.FgGreen, skipped, "skipped", color.FgYellow)
+	if text {
+		helpers.PrintSummary(r.Summary.Created, "created", color.FgGreen, r.Summary.Skipped, "skipped", color.FgYellow)
+	}
+	if err := r.Emit(format, output); err != nil {
+		return err
+	}
+	if r.Summary.Error > 0 {
+		return cli.Exit("", 1)
+	}
 	return nil
 }
 
-func initRunSingle(template string, readmePath string) error {
+func initRunSingle(template string, readmePath string, format string, output string) error {
+	cfg, err := config.LoadWithReadme(".", readmePath)
+	if err != nil {
+		return err
+	}
+	readmePath = cfg.ReadmeFilename(".")
+	// The README can be created before the action file, the action of the report is empty then
+	actionPath, _ := helpers.FindActionFile()
+	text := format == report.FormatText
+	r := report.New("init")
 	// Check if file already exists
-	if _, err := os.Stat(readmePath); err == nil {
-		return fmt.Errorf("%s already exists", readmePath)
+	if _, statErr := os.Stat(readmePath); statErr == nil {
+		err = fmt.Errorf("%s already exists", readmePath)
+	} else {
+		err = createReadmeFromTemplate(templateOf(template, cfg), readmePath)
+	}
+	if err != nil {
+		if text {
+			return err
+		}
+		r.Failed(actionPath, readmePath, err)
+		if err := r.Emit(format, output); err != nil {
+			return err
+		}
+		return cli.Exit("", 1)
 	}
 
-	if err := createReadmeFromTemplate(template, readmePath); err != nil {
-		return err
+	r.Add(report.Entry{Action: actionPath, Readme: readmePath, Status: report.StatusCreated})
+	if text {
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Created: %s\n", green("✓"), readmePath)
 	}
+	return r.Emit(format, output)
+}
 
-	green := color.New(color.FgGreen).SprintFunc()
-	fmt.Printf("%s Created: %s\n", green("✓"), readmePath)
-	return nil
+// templateOf returns the template given with the template flag, or else the template of the configuration
+func templateOf(template string, c *config.Config) string {
+	switch {
+	case template != "":
+		return template
+	case c.Template != "":
+		return c.Template
+	default:
+		return config.DefaultTemplate
+	}
 }
 
+// createReadmeFromTemplate creates the README from the builtin default template or a markdown file
 func createReadmeFromTemplate(template string, readmePath string) error {
 	switch template {
-	case "default":
+	case config.DefaultTemplate:
 		file, err := f.ReadFile("templates/default.md")
 		if err != nil {
 			return err
 		}
 		return writeToFile(readmePath, string(file))
 	default:
-		return fmt.Errorf("unknown template: %s", template)
+		file, err := os.ReadFile(template)
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("unknown template: %s", template)
+		}
+		if err != nil {
+			return err
+		}
+		return writeToFile(readmePath, string(file))
 	}
 }
 
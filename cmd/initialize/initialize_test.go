@@ -1,11 +1,13 @@
 package initialize_test
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/reakaleek/gh-action-readme/cmd/initialize"
+	"github.com/reakaleek/gh-action-readme/internal/report"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/urfave/cli/v2"
@@ -37,3 +39,52 @@ func TestInitCommand(t *testing.T) {
 	assert.Contains(t, contentStr, "<!--inputs-->")
 	assert.Contains(t, contentStr, "<!--outputs-->")
 }
+
+// TestInitCommandConfigTemplate tests that the README is created from the template of the configuration file
+func TestInitCommandConfigTemplate(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "docs"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "docs", "readme.md"), []byte("# <!--name--><!--/name-->\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".gh-action-readme.yml"), []byte("readme: DOCS.md\ntemplates:\n  init: docs/readme.md\n"), 0644))
+
+	originalWd, _ := os.Getwd()
+	defer func() { _ = os.Chdir(originalWd) }()
+	require.NoError(t, os.Chdir(tmpDir))
+
+	app := &cli.App{
+		Commands: []*cli.Command{initialize.NewCommand()},
+	}
+
+	err := app.Run([]string{"app", "init"})
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "DOCS.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "# <!--name--><!--/name-->\n", string(content))
+}
+
+// TestInitCommandJSONReport tests that the report of a single README names the action file of the directory
+func TestInitCommandJSONReport(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "action.yaml"), []byte("name: Test\ndescription: Test\nruns:\n  using: composite\n  steps: []\n"), 0644))
+
+	originalWd, _ := os.Getwd()
+	defer func() { _ = os.Chdir(originalWd) }()
+	require.NoError(t, os.Chdir(tmpDir))
+
+	app := &cli.App{
+		Commands: []*cli.Command{initialize.NewCommand()},
+	}
+
+	err := app.Run([]string{"app", "init", "--format", "json", "--output", "report.json"})
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "report.json"))
+	require.NoError(t, err)
+	var r report.Report
+	require.NoError(t, json.Unmarshal(content, &r))
+	require.Len(t, r.Actions, 1)
+	assert.Equal(t, "action.yaml", r.Actions[0].Action)
+	assert.Equal(t, "README.md", r.Actions[0].Readme)
+	assert.Equal(t, report.StatusCreated, r.Actions[0].Status)
+}
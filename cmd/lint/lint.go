@@ -0,0 +1,86 @@
+package lint
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/reakaleek/gh-action-readme/internal/action"
+	"github.com/reakaleek/gh-action-readme/internal/helpers"
+	"github.com/urfave/cli/v2"
+)
+
+func NewCommand() *cli.Command {
+	var recursive bool
+	return &cli.Command{
+		Name:      "lint",
+		Usage:     "Check the inputs of composite actions against their usage in the steps",
+		ArgsUsage: "[action.yml...]",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:        "recursive",
+				Aliases:     []string{"r"},
+				Value:       false,
+				Destination: &recursive,
+				Usage:       "Search recursively for all action.yml/action.yaml files",
+			},
+		},
+		Action: func(ctx *cli.Context) error {
+			return lintRun(ctx.Args().Slice(), recursive)
+		},
+	}
+}
+
+func lintRun(actionFiles []string, recursive bool) error {
+	var err error
+	if len(actionFiles) == 0 && recursive {
+		actionFiles, err = helpers.FindAllActionFiles(".")
+		if err != nil {
+			return err
+		}
+		if len(actionFiles) == 0 {
+			return fmt.Errorf("no action.yml or action.yaml files found")
+		}
+		helpers.PrintHeader("Found %d action file(s)\n\n", len(actionFiles))
+	}
+	if len(actionFiles) == 0 {
+		actionPath, err := helpers.FindActionFile()
+		if err != nil {
+			return err
+		}
+		actionFiles = []string{actionPath}
+	}
+
+	clean := 0
+	withIssues := 0
+
+	green := color.New(color.FgGreen).SprintFunc()
+	parser := action.NewParser()
+
+	for _, actionPath := range actionFiles {
+		a, err := parser.Parse(actionPath)
+		if err != nil {
+			return fmt.Errorf("error parsing %s: %w", actionPath, err)
+		}
+		issues := a.LintInputs()
+		if len(issues) > 0 {
+			violations := make([]action.Violation, len(issues))
+			for i, issue := range issues {
+				violations[i] = issue.Violation(actionPath)
+			}
+			helpers.PrintViolations(violations)
+			withIssues++
+		} else {
+			fmt.Printf("%s %s\n", green("✓"), actionPath)
+			clean++
+		}
+	}
+
+	if len(actionFiles) > 1 {
+		helpers.PrintSummary(clean, "clean", color.FgGreen, withIssues, "with issues", color.FgRed)
+	}
+
+	if withIssues > 0 {
+		return cli.Exit("", 1)
+	}
+	return nil
+}
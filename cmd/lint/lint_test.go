@@ -0,0 +1,62 @@
+package lint_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/reakaleek/gh-action-readme/cmd/lint"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+func runLint(t *testing.T, args ...string) error {
+	t.Helper()
+	app := &cli.App{
+		Commands:       []*cli.Command{lint.NewCommand()},
+		ExitErrHandler: func(_ *cli.Context, _ error) {},
+	}
+	return app.Run(append([]string{"app", "lint"}, args...))
+}
+
+func TestLintCommand_Clean(t *testing.T) {
+	actionPath := filepath.Join(t.TempDir(), "action.yml")
+	actionYML := `name: Test
+description: Test
+inputs:
+  name:
+    description: 'Who to greet'
+runs:
+  using: composite
+  steps:
+    - run: echo "Hello ${{ inputs.name }}"
+      shell: bash
+`
+	require.NoError(t, os.WriteFile(actionPath, []byte(actionYML), 0644))
+
+	err := runLint(t, actionPath)
+	assert.NoError(t, err)
+}
+
+func TestLintCommand_Issues(t *testing.T) {
+	actionPath := filepath.Join(t.TempDir(), "action.yml")
+	actionYML := `name: Test
+description: Test
+inputs:
+  name:
+    description: 'Who to greet'
+runs:
+  using: composite
+  steps:
+    - run: echo "Hello ${{ inputs.nme }}"
+      shell: bash
+`
+	require.NoError(t, os.WriteFile(actionPath, []byte(actionYML), 0644))
+
+	err := runLint(t, actionPath)
+
+	var exitErr cli.ExitCoder
+	require.ErrorAs(t, err, &exitErr)
+	assert.Equal(t, 1, exitErr.ExitCode())
+}
@@ -3,10 +3,13 @@ package precommit
 import (
 	"errors"
 	"github.com/reakaleek/gh-action-readme/internal/action"
+	"github.com/reakaleek/gh-action-readme/internal/config"
+	"github.com/reakaleek/gh-action-readme/internal/helpers"
 	"github.com/reakaleek/gh-action-readme/internal/markdown"
 	"github.com/urfave/cli/v2"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 )
 
@@ -35,56 +38,111 @@ func NewCommand() *cli.Command {
 					return err
 				}
 			}
-			files := ctx.Args().Slice()
-			for _, file := range files {
-				dir := filepath.Dir(file)
-				readmePath := filepath.Join(dir, "README.md")
-				
-				// Look for action.yml or action.yaml
-				actionPath, err := findActionFile(dir)
-				if err != nil {
-					// No action file found, skip this directory
-					continue
-				}
-				doc, err := markdown.NewDoc(readmePath)
-				var oldDoc markdown.Doc
-				if err != nil {
-					if !errors.Is(err, os.ErrNotExist) {
-						return err
-					}
-					// README.md doesn't exist yet — use an empty doc as the "before"
-					// state so any generated content counts as a change.
-					emptyDoc := markdown.NewEmptyDoc(readmePath)
-					oldDoc = emptyDoc.Copy()
-					doc, err = markdown.NewDocOrCreate(readmePath)
-					if err != nil {
-						return err
-					}
-				} else {
-					oldDoc = doc.Copy()
-				}
-				parser := action.NewParser()
-				a, err := parser.Parse(actionPath)
-				if err != nil {
-					return err
-				}
-				err = doc.Update(&a)
-				if err != nil {
+			dirs, err := actionDirs(ctx.Args().Slice())
+			if err != nil {
+				return err
+			}
+			for _, dir := range dirs {
+				if err := updateDir(dir); err != nil {
 					return err
 				}
-				if !oldDoc.Equals(*doc) {
-					err = doc.WriteToFile()
-					if err != nil {
-						return err
-					}
-					println(doc.GetName())
-				}
 			}
 			return nil
 		},
 	}
 }
 
+// actionDirs returns the directories of the actions whose README is updated for the changed files.
+// A changed configuration file updates all actions below its directory, a changed markdown file only
+// updates the action next to it if the file is its README.
+func actionDirs(files []string) ([]string, error) {
+	var dirs []string
+	add := func(dir string) {
+		if !slices.Contains(dirs, dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	for _, file := range files {
+		dir := filepath.Dir(file)
+		name := filepath.Base(file)
+		switch {
+		case name == config.FileName:
+			actionFiles, err := helpers.FindAllActionFiles(dir)
+			if err != nil {
+				return nil, err
+			}
+			for _, actionPath := range actionFiles {
+				add(filepath.Dir(actionPath))
+			}
+		case name == "action.yml" || name == "action.yaml":
+			add(dir)
+		default:
+			cfg, err := config.Load(dir)
+			if err != nil {
+				return nil, err
+			}
+			if cfg.IsReadme(name) {
+				add(dir)
+			}
+		}
+	}
+	return dirs, nil
+}
+
+// updateDir updates the README of the action in the directory, if the configuration includes it
+func updateDir(dir string) error {
+	// Look for action.yml or action.yaml
+	actionPath, err := findActionFile(dir)
+	if err != nil {
+		// No action file found, skip this directory
+		return nil
+	}
+	cfg, err := config.Load(dir)
+	if err != nil {
+		return err
+	}
+	if !cfg.Includes(actionPath) {
+		return nil
+	}
+	readmePath := filepath.Join(dir, cfg.ReadmeFilename(dir))
+	existingDoc, err := markdown.NewDoc(readmePath)
+	var oldDoc markdown.Doc
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		// README.md doesn't exist yet — use an empty doc as the "before"
+		// state so any generated content counts as a change.
+		emptyDoc := markdown.NewEmptyDoc(readmePath)
+		oldDoc = emptyDoc.Copy()
+	} else {
+		oldDoc = existingDoc.Copy()
+	}
+	// Opened like update does, so missing end markers of legacy sections are added
+	doc, err := markdown.NewDocOrCreate(readmePath)
+	if err != nil {
+		return err
+	}
+	doc.Configure(cfg.Settings())
+	parser := action.NewParser()
+	a, err := parser.Parse(actionPath)
+	if err != nil {
+		return err
+	}
+	err = doc.Update(&a)
+	if err != nil {
+		return err
+	}
+	if !oldDoc.Equals(*doc) {
+		err = doc.WriteToFile()
+		if err != nil {
+			return err
+		}
+		println(doc.GetName())
+	}
+	return nil
+}
+
 func findActionFile(dir string) (string, error) {
 	// Check for action.yml first (more common)
 	ymlPath := filepath.Join(dir, "action.yml")
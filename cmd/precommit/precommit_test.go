@@ -3,6 +3,7 @@ package precommit
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -68,3 +69,69 @@ description: My Description`
 
 	assert.Equal(t, string(first), string(second), "README.md should not change when already up-to-date")
 }
+
+// TestPrecommit_Config verifies that the README filename, the placeholder defaults and the env of the
+// configuration file are used.
+func TestPrecommit_Config(t *testing.T) {
+	tmpDir := t.TempDir()
+	actionPath := filepath.Join(tmpDir, "action.yml")
+	readmePath := filepath.Join(tmpDir, "DOCS.md")
+
+	actionContent := `name: My Action
+description: My Description
+inputs:
+  token:
+    description: The token`
+	require.NoError(t, os.WriteFile(actionPath, []byte(actionContent), 0644))
+	config := `readme: DOCS.md
+defaults:
+  inputs:
+    style: list
+env:
+  ACTION_VERSION: v1.2.3`
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".gh-action-readme.yml"), []byte(config), 0644))
+	readme := "<!--inputs-->\n<!--/inputs-->\n<!--usage action=\"owner/repo\" version=\"env:ACTION_VERSION\"-->\n```yaml\n- uses: owner/repo@v1\n```\n<!--/usage-->\n"
+	require.NoError(t, os.WriteFile(readmePath, []byte(readme), 0644))
+
+	err := runPrecommit(t, []string{actionPath})
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(readmePath)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "- `token`")
+	assert.Contains(t, string(content), "- uses: owner/repo@v1.2.3")
+	_, err = os.Stat(filepath.Join(tmpDir, "README.md"))
+	assert.True(t, os.IsNotExist(err), "README.md should not be created")
+}
+
+// TestPrecommit_ChangedFiles verifies which changed files update the README of an action.
+// The configured README and a configuration file above the action update it, other markdown files don't.
+func TestPrecommit_ChangedFiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		updated bool
+	}{
+		{name: "configured readme", file: "actions/deploy/DOCS.md", updated: true},
+		{name: "other markdown file", file: "actions/deploy/CHANGELOG.md", updated: false},
+		{name: "parent configuration file", file: ".gh-action-readme.yml", updated: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			actionDir := filepath.Join(tmpDir, "actions", "deploy")
+			require.NoError(t, os.MkdirAll(actionDir, 0755))
+			require.NoError(t, os.WriteFile(filepath.Join(actionDir, "action.yml"), []byte("name: Deploy\ndescription: Deploys"), 0644))
+			require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".gh-action-readme.yml"), []byte("readme: DOCS.md\n"), 0644))
+			readmePath := filepath.Join(actionDir, "DOCS.md")
+			require.NoError(t, os.WriteFile(readmePath, []byte("# <!--name--><!--/name-->\n"), 0644))
+
+			err := runPrecommit(t, []string{filepath.Join(tmpDir, tt.file)})
+			require.NoError(t, err)
+
+			content, err := os.ReadFile(readmePath)
+			require.NoError(t, err)
+			assert.Equal(t, tt.updated, strings.Contains(string(content), "# <!--name-->Deploy<!--/name-->"))
+		})
+	}
+}
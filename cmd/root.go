@@ -3,8 +3,10 @@ package cmd
 import (
 	"github.com/reakaleek/gh-action-readme/cmd/diff"
 	"github.com/reakaleek/gh-action-readme/cmd/initialize"
+	"github.com/reakaleek/gh-action-readme/cmd/lint"
 	"github.com/reakaleek/gh-action-readme/cmd/precommit"
 	"github.com/reakaleek/gh-action-readme/cmd/update"
+	"github.com/reakaleek/gh-action-readme/cmd/validate"
 	"github.com/urfave/cli/v2"
 	"log"
 	"os"
@@ -19,6 +21,8 @@ func Execute() {
 			update.NewCommand(),
 			initialize.NewCommand(),
 			precommit.NewCommand(),
+			validate.NewCommand(),
+			lint.NewCommand(),
 		},
 	}
 	if err := app.Run(os.Args); err != nil {
@@ -2,23 +2,28 @@ package update
 
 import (
 	"fmt"
-	"path/filepath"
+	"os"
 
 	"github.com/fatih/color"
-	"github.com/reakaleek/gh-action-readme/internal/action"
+	"github.com/reakaleek/gh-action-readme/internal/config"
 	"github.com/reakaleek/gh-action-readme/internal/helpers"
 	"github.com/reakaleek/gh-action-readme/internal/markdown"
+	"github.com/reakaleek/gh-action-readme/internal/report"
 	"github.com/urfave/cli/v2"
 )
 
 func NewCommand() *cli.Command {
 	var readmePath string
+	var workflowPath string
 	var recursive bool
+	var validate bool
 	var _ string // unused actionPath for backwards compatibility
+	var format string
+	var output string
 	return &cli.Command{
 		Name:  "update",
 		Usage: "Update README.md",
-		Flags: []cli.Flag{
+		Flags: append([]cli.Flag{
 			&cli.StringFlag{
 				Name:   "action",
 				Hidden: true,
@@ -26,8 +31,13 @@ func NewCommand() *cli.Command {
 			},
 			&cli.StringFlag{
 				Name:        "readme",
-				Value:       "README.md",
 				Destination: &readmePath,
+				Usage:       "README filename, defaults to the readme of " + config.FileName + " or README.md",
+			},
+			&cli.StringFlag{
+				Name:        "workflow",
+				Destination: &workflowPath,
+				Usage:       "Document a reusable workflow (on: workflow_call) instead of action.yml/action.yaml",
 			},
 			&cli.BoolFlag{
 				Name:        "recursive",
@@ -36,104 +46,190 @@ func NewCommand() *cli.Command {
 				Destination: &recursive,
 				Usage:       "Search recursively for all action.yml/action.yaml files",
 			},
-		},
+			&cli.BoolFlag{
+				Name:        "validate",
+				Value:       false,
+				Destination: &validate,
+				Usage:       "Validate action.yml/action.yaml against the metadata syntax before generating the documentation",
+			},
+		}, report.Flags(&format, &output)...),
 		Action: func(ctx *cli.Context) error {
-			return updateRun(readmePath, recursive)
+			return updateRun(readmePath, workflowPath, recursive, validate, format, output)
 		},
 	}
 }
 
-func updateRun(readmePath string, recursive bool) error {
+func updateRun(readmePath string, workflowPath string, recursive bool, validate bool, format string, output string) error {
 	if recursive {
-		return updateRunRecursive(readmePath)
+		return updateRunRecursive(readmePath, validate, format, output)
+	}
+	cfg, err := config.LoadWithReadme(".", readmePath)
+	if err != nil {
+		return err
+	}
+	readmePath = cfg.ReadmeFilename(".")
+	if workflowPath != "" {
+		return updateSingleAction(workflowPath, readmePath, cfg.Settings(), validate, format, output)
 	}
-	return updateRunSingle(readmePath)
+	return updateRunSingle(readmePath, cfg.Settings(), validate, format, output)
 }
 
-func updateRunRecursive(readmeFilename string) error {
-	actionFiles, err := helpers.FindAllActionFiles(".")
+func updateRunRecursive(readmeFilename string, validate bool, format string, output string) error {
+	found, err := config.Discover(readmeFilename)
 	if err != nil {
 		return err
 	}
 	
-	if len(actionFiles) == 0 {
+	if len(found.Actions) == 0 && len(found.Workflows) == 0 {
 		return fmt.Errorf("no action.yml or action.yaml files found")
 	}
 	
-	helpers.PrintHeader("Found %d action file(s)\n\n", len(actionFiles))
-	
-	updated := 0
-	unchanged := 0
+	text := format == report.FormatText
+	if text {
+		helpers.PrintHeader("Found %d action file(s)\n\n", len(found.Actions))
+		if len(found.Workflows) > 0 {
+			helpers.PrintHeader("Found %d documented reusable workflow(s)\n\n", len(found.Workflows))
+		}
+	}
 	
 	green := color.New(color.FgGreen).SprintFunc()
 	yellow := color.New(color.FgYellow).SprintFunc()
 	
-	for _, actionPath := range actionFiles {
-		dir := filepath.Dir(actionPath)
-		readmePath := filepath.Join(dir, readmeFilename)
+	if validate {
+		if err := validateActionFiles(found.ActionFiles()); err != nil {
+			return err
+		}
+	}
+	
+	r := report.New("update")
+	
+	for _, t := range found.Targets() {
+		actionPath, readmePath := t.Action, t.Readme
 		
-		wasUpdated, err := updateSingleActionWithResult(actionPath, readmePath)
+		entry, issues, err := updateReadme(actionPath, readmePath, t.Config.Settings())
 		if err != nil {
-			return fmt.Errorf("error updating %s: %w", readmePath, err)
+			if text {
+				return fmt.Errorf("error updating %s: %w", readmePath, err)
+			}
+			// The JSON report has an entry for every README, so the other READMEs are still updated
+			r.Failed(actionPath, readmePath, err)
+			continue
 		}
+		r.Add(entry)
 		
-		if wasUpdated {
-			fmt.Printf("%s Updated: %s\n", green("✓"), readmePath)
-			updated++
-		} else {
+		if !text {
+			continue
+		}
+		helpers.PrintUsageIssues(issues)
+		if entry.Status == report.StatusUpToDate {
 			fmt.Printf("%s Unchanged: %s\n", yellow("○"), readmePath)
-			unchanged++
+		} else {
+			fmt.Printf("%s Updated: %s\n", green("✓"), readmePath)
 		}
 	}
 	
-	helpers.PrintSummary(updated, "updated", color.FgGreen, unchanged, "unchanged", color.FgYellow)
+	if text {
+		helpers.PrintSummary(r.Summary.Updated+r.Summary.Created, "updated", color.FgGreen, r.Summary.UpToDate, "unchanged", color.FgYellow)
+	}
+	if err := r.Emit(format, output); err != nil {
+		return err
+	}
+	if r.Summary.Error > 0 {
+		return cli.Exit("", 1)
+	}
 	return nil
 }
 
-func updateSingleActionWithResult(actionPath, readmePath string) (bool, error) {
-	actionParser := action.NewParser()
-	a, err := actionParser.Parse(actionPath)
+// updateReadme updates the README of the action and returns its report entry.
+// The stale sections and the patch of the entry are the changes that were written.
+func updateReadme(actionPath, readmePath string, settings markdown.Settings) (report.Entry, []markdown.UsageIssue, error) {
+	a, err := helpers.ParseActionFile(actionPath)
 	if err != nil {
-		return false, err
+		return report.Entry{}, nil, err
 	}
+	_, statErr := os.Stat(readmePath)
+	exists := statErr == nil
 	doc, err := markdown.NewDocOrCreate(readmePath)
 	if err != nil {
-		return false, err
+		return report.Entry{}, nil, err
 	}
+	doc.Configure(settings)
 	oldDoc := doc.Copy()
-	err = doc.Update(&a)
+	err = doc.Update(a)
 	if err != nil {
-		return false, err
+		return report.Entry{}, nil, err
+	}
+	issues := doc.LintUsage(a)
+	entry := report.Entry{
+		Action:      actionPath,
+		Readme:      readmePath,
+		Status:      report.StatusUpToDate,
+		UsageIssues: issues,
 	}
 
 	if doc.Equals(oldDoc) {
-		return false, nil
+		return entry, issues, nil
 	}
 
 	err = doc.WriteToFile()
 	if err != nil {
-		return false, err
+		return report.Entry{}, nil, err
+	}
+	before := &oldDoc
+	entry.Status = report.StatusUpdated
+	if !exists {
+		// A new README is compared with an empty one instead of the template it was created from
+		before = markdown.NewEmptyDoc(readmePath)
+		entry.Status = report.StatusCreated
 	}
-	return true, nil
+	entry.StaleSections = report.StaleSections(before.StaleSections(doc, a))
+	entry.Patch = before.Patch(doc, markdown.DefaultContext)
+	entry.Line = before.FirstChangedLine(doc)
+	return entry, issues, nil
 }
 
-func updateSingleAction(actionPath, readmePath string) error {
-	wasUpdated, err := updateSingleActionWithResult(actionPath, readmePath)
+func updateSingleAction(actionPath, readmePath string, settings markdown.Settings, validate bool, format string, output string) error {
+	if validate && actionPath != "" {
+		if err := validateActionFiles([]string{actionPath}); err != nil {
+			return err
+		}
+	}
+	text := format == report.FormatText
+	r := report.New("update")
+	entry, issues, err := updateReadme(actionPath, readmePath, settings)
 	if err != nil {
-		return err
+		if text {
+			return err
+		}
+		r.Failed(actionPath, readmePath, err)
+		if err := r.Emit(format, output); err != nil {
+			return err
+		}
+		return cli.Exit("", 1)
 	}
-	if wasUpdated {
-		green := color.New(color.FgGreen).SprintFunc()
-		fmt.Printf("%s Updated: %s\n", green("✓"), readmePath)
+	r.Add(entry)
+	if text {
+		helpers.PrintUsageIssues(issues)
+		if entry.Status != report.StatusUpToDate {
+			green := color.New(color.FgGreen).SprintFunc()
+			fmt.Printf("%s Updated: %s\n", green("✓"), readmePath)
+		}
 	}
-	return nil
+	return r.Emit(format, output)
 }
 
-func updateRunSingle(readmePath string) error {
-	actionPath, err := helpers.FindActionFile()
+func updateRunSingle(readmePath string, settings markdown.Settings, validate bool, format string, output string) error {
+	actionPath, err := config.FindActionFileOrReadme(readmePath)
 	if err != nil {
 		return err
 	}
 
-	return updateSingleAction(actionPath, readmePath)
+	return updateSingleAction(actionPath, readmePath, settings, validate, format, output)
+}
+
+func validateActionFiles(actionFiles []string) error {
+	if err := helpers.ValidateActionFiles(actionFiles); err != nil {
+		return cli.Exit(err.Error(), 1)
+	}
+	return nil
 }
@@ -1,11 +1,13 @@
 package update_test
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/reakaleek/gh-action-readme/cmd/update"
+	"github.com/reakaleek/gh-action-readme/internal/report"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/urfave/cli/v2"
@@ -42,8 +44,11 @@ func TestUpdateCommand(t *testing.T) {
 	initialReadme := `# Test
 <!--name--><!--/name-->
 <!--description-->
+<!--/description-->
 <!--inputs-->
+<!--/inputs-->
 <!--outputs-->
+<!--/outputs-->
 `
 	err := os.WriteFile(readmePath, []byte(initialReadme), 0644)
 	require.NoError(t, err)
@@ -81,8 +86,11 @@ func TestUpdateCommandBackwardsCompatible(t *testing.T) {
 	initialReadme := `# Test
 <!--name--><!--/name-->
 <!--description-->
+<!--/description-->
 <!--inputs-->
+<!--/inputs-->
 <!--outputs-->
+<!--/outputs-->
 `
 	err := os.WriteFile(readmePath, []byte(initialReadme), 0644)
 	require.NoError(t, err)
@@ -129,7 +137,9 @@ inputs:
 		
 		readme := `<!--name--><!--/name-->
 <!--description-->
+<!--/description-->
 <!--inputs-->
+<!--/inputs-->
 `
 		err = os.WriteFile(filepath.Join(actionDir, "README.md"), []byte(readme), 0644)
 		require.NoError(t, err)
@@ -178,6 +188,7 @@ description: Test action
 			
 			readme := `<!--name--><!--/name-->
 <!--description-->
+<!--/description-->
 `
 			readmePath := filepath.Join(tmpDir, "README.md")
 			err = os.WriteFile(readmePath, []byte(readme), 0644)
@@ -201,3 +212,323 @@ description: Test action
 		})
 	}
 }
+
+// TestUpdateCommandWorkflow tests documenting a reusable workflow
+func TestUpdateCommandWorkflow(t *testing.T) {
+	tmpDir := t.TempDir()
+	workflowsDir := filepath.Join(tmpDir, ".github", "workflows")
+	require.NoError(t, os.MkdirAll(workflowsDir, 0755))
+
+	workflowYML := `name: Build
+on:
+  workflow_call:
+    inputs:
+      publish:
+        description: 'Whether to publish'
+        type: boolean
+    secrets:
+      registry-token:
+        description: 'Registry token'
+        required: true
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo build
+`
+	workflowPath := filepath.Join(workflowsDir, "build.yml")
+	require.NoError(t, os.WriteFile(workflowPath, []byte(workflowYML), 0644))
+
+	readme := `# <!--name--><!--/name-->
+<!--inputs-->
+<!--/inputs-->
+<!--secrets-->
+<!--/secrets-->
+`
+	readmePath := filepath.Join(workflowsDir, "build.md")
+	require.NoError(t, os.WriteFile(readmePath, []byte(readme), 0644))
+
+	originalWd, _ := os.Getwd()
+	defer func() { _ = os.Chdir(originalWd) }()
+	require.NoError(t, os.Chdir(tmpDir))
+
+	app := &cli.App{
+		Commands: []*cli.Command{update.NewCommand()},
+	}
+
+	t.Run("single", func(t *testing.T) {
+		err := app.Run([]string{"app", "update", "--workflow", ".github/workflows/build.yml", "--readme", ".github/workflows/build.md"})
+		assert.NoError(t, err)
+
+		content, err := os.ReadFile(readmePath)
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "# <!--name-->Build<!--/name-->")
+		assert.Contains(t, string(content), "`boolean`")
+		assert.Contains(t, string(content), "`registry-token`")
+	})
+
+	t.Run("recursive", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(readmePath, []byte(readme), 0644))
+
+		err := app.Run([]string{"app", "update", "--recursive"})
+		assert.NoError(t, err)
+
+		content, err := os.ReadFile(readmePath)
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "`registry-token`")
+	})
+}
+
+// TestUpdateCommandValidate tests that --validate fails on an invalid action.yml
+func TestUpdateCommandValidate(t *testing.T) {
+	tmpDir := t.TempDir()
+	actionYML := `name: Test Action
+description: A test action
+inputs:
+  test-input:
+    description: 'Test input'
+    require: true
+runs:
+  using: composite
+  steps: []
+`
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "action.yml"), []byte(actionYML), 0644))
+	readmePath := filepath.Join(tmpDir, "README.md")
+	readme := "<!--inputs-->\n<!--/inputs-->\n"
+	require.NoError(t, os.WriteFile(readmePath, []byte(readme), 0644))
+
+	originalWd, _ := os.Getwd()
+	defer func() { _ = os.Chdir(originalWd) }()
+	require.NoError(t, os.Chdir(tmpDir))
+
+	app := &cli.App{
+		Commands:       []*cli.Command{update.NewCommand()},
+		ExitErrHandler: func(_ *cli.Context, _ error) {},
+	}
+
+	err := app.Run([]string{"app", "update", "--validate", "--readme", readmePath})
+	assert.Error(t, err)
+
+	// README must not be touched if the action is invalid
+	content, err := os.ReadFile(readmePath)
+	require.NoError(t, err)
+	assert.Equal(t, readme, string(content))
+}
+
+// TestUpdateCommandReferencedActions tests a README that documents actions in subdirectories
+func TestUpdateCommandReferencedActions(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, name := range []string{"build", "deploy"} {
+		actionYML := "name: " + name + "\ndescription: The " + name + " action\ninputs:\n  " + name + "-input:\n    description: 'Input'\n"
+		require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, name), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, name, "action.yml"), []byte(actionYML), 0644))
+	}
+	readmePath := filepath.Join(tmpDir, "README.md")
+	initialReadme := `# Actions
+## <!--name action="./build"--><!--/name-->
+<!--inputs action="./build"-->
+<!--/inputs-->
+## <!--name action="./deploy"--><!--/name-->
+<!--inputs action="./deploy"-->
+<!--/inputs-->
+`
+	err := os.WriteFile(readmePath, []byte(initialReadme), 0644)
+	require.NoError(t, err)
+
+	// Change to tmpDir, there is no action.yml in it
+	originalWd, _ := os.Getwd()
+	defer func() { _ = os.Chdir(originalWd) }()
+	err = os.Chdir(tmpDir)
+	require.NoError(t, err)
+
+	app := &cli.App{
+		Commands: []*cli.Command{update.NewCommand()},
+	}
+
+	err = app.Run([]string{"app", "update", "--readme", readmePath})
+	assert.NoError(t, err)
+
+	content, err := os.ReadFile(readmePath)
+	require.NoError(t, err)
+	contentStr := string(content)
+	assert.Contains(t, contentStr, `## <!--name action="./build"-->build<!--/name-->`)
+	assert.Contains(t, contentStr, `## <!--name action="./deploy"-->deploy<!--/name-->`)
+	assert.Contains(t, contentStr, "`build-input`")
+	assert.Contains(t, contentStr, "`deploy-input`")
+}
+
+// TestUpdateCommandWithoutAction tests that a README without an action file fails
+// unless it references the actions of other directories
+func TestUpdateCommandWithoutAction(t *testing.T) {
+	tmpDir := t.TempDir()
+	readmePath := filepath.Join(tmpDir, "README.md")
+	initialReadme := "# <!--name--><!--/name-->\n<!--inputs-->\n<!--/inputs-->\n"
+	require.NoError(t, os.WriteFile(readmePath, []byte(initialReadme), 0644))
+
+	originalWd, _ := os.Getwd()
+	defer func() { _ = os.Chdir(originalWd) }()
+	require.NoError(t, os.Chdir(tmpDir))
+
+	app := &cli.App{
+		Commands: []*cli.Command{update.NewCommand()},
+	}
+
+	err := app.Run([]string{"app", "update"})
+	assert.ErrorContains(t, err, "neither action.yml nor action.yaml found")
+
+	content, err := os.ReadFile(readmePath)
+	require.NoError(t, err)
+	assert.Equal(t, initialReadme, string(content))
+}
+
+// TestRecursiveUpdateActionsIndex tests that a top-level README without an action is updated in recursive mode
+func TestRecursiveUpdateActionsIndex(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, dir := range []string{"deploy", "build"} {
+		actionDir := filepath.Join(tmpDir, dir)
+		require.NoError(t, os.MkdirAll(actionDir, 0755))
+		actionYML := "name: " + dir + "\ndescription: The " + dir + " action. It does things.\n"
+		require.NoError(t, os.WriteFile(filepath.Join(actionDir, "action.yml"), []byte(actionYML), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(actionDir, "README.md"), []byte("<!--name--><!--/name-->\n"), 0644))
+	}
+	readmePath := filepath.Join(tmpDir, "README.md")
+	require.NoError(t, os.WriteFile(readmePath, []byte("# Actions\n<!--actions-index-->\n<!--/actions-index-->\n"), 0644))
+
+	originalWd, _ := os.Getwd()
+	defer func() { _ = os.Chdir(originalWd) }()
+	_ = os.Chdir(tmpDir)
+
+	app := &cli.App{
+		Commands: []*cli.Command{update.NewCommand()},
+	}
+
+	err := app.Run([]string{"app", "update", "--recursive"})
+	assert.NoError(t, err)
+
+	content, err := os.ReadFile(readmePath)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "| build  | The build action.  | `build`  | [README.md](build/README.md)  |\n| deploy | The deploy action. | `deploy` | [README.md](deploy/README.md) |")
+}
+
+// TestRecursiveUpdateRootReadme tests that the top-level README is only updated if it references actions,
+// and that its placeholders without an action attribute fail
+func TestRecursiveUpdateRootReadme(t *testing.T) {
+	tests := []struct {
+		name   string
+		readme string
+		err    bool
+	}{
+		{name: "actions-index with inputs", readme: "<!--actions-index-->\n<!--/actions-index-->\n<!--inputs-->\n<!--/inputs-->\n", err: true},
+		{name: "inputs only", readme: "<!--inputs-->\n<!--/inputs-->\n", err: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			actionDir := filepath.Join(tmpDir, "deploy")
+			require.NoError(t, os.MkdirAll(actionDir, 0755))
+			require.NoError(t, os.WriteFile(filepath.Join(actionDir, "action.yml"), []byte("name: deploy\ndescription: Deploys.\n"), 0644))
+			readmePath := filepath.Join(tmpDir, "README.md")
+			require.NoError(t, os.WriteFile(readmePath, []byte(tt.readme), 0644))
+
+			originalWd, _ := os.Getwd()
+			defer func() { _ = os.Chdir(originalWd) }()
+			require.NoError(t, os.Chdir(tmpDir))
+
+			app := &cli.App{
+				Commands: []*cli.Command{update.NewCommand()},
+			}
+
+			err := app.Run([]string{"app", "update", "--recursive"})
+			if tt.err {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			content, err := os.ReadFile(readmePath)
+			require.NoError(t, err)
+			assert.Equal(t, tt.readme, string(content))
+		})
+	}
+}
+
+// TestUpdateCommandJSONReport tests the JSON report of a README that is created by update
+func TestUpdateCommandJSONReport(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	readmePath := filepath.Join(tmpDir, "README.md")
+	reportPath := filepath.Join(tmpDir, "report.json")
+
+	originalWd, _ := os.Getwd()
+	defer func() { _ = os.Chdir(originalWd) }()
+	err := os.Chdir(tmpDir)
+	require.NoError(t, err)
+
+	app := &cli.App{
+		Commands: []*cli.Command{update.NewCommand()},
+	}
+
+	err = app.Run([]string{"app", "update", "--readme", readmePath, "--format", "json", "--output", reportPath})
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(reportPath)
+	require.NoError(t, err)
+	var r report.Report
+	require.NoError(t, json.Unmarshal(content, &r))
+	assert.Equal(t, "update", r.Command)
+	assert.Equal(t, report.Summary{Total: 1, Created: 1}, r.Summary)
+	require.Len(t, r.Actions, 1)
+	assert.Equal(t, "action.yml", r.Actions[0].Action)
+	assert.Equal(t, report.StatusCreated, r.Actions[0].Status)
+	assert.Contains(t, r.Actions[0].Patch, "--- /dev/null\n")
+	assert.Contains(t, r.Actions[0].Patch, "+# <!--name-->Test Action<!--/name-->\n")
+
+	// A second update leaves the README up-to-date
+	err = app.Run([]string{"app", "update", "--readme", readmePath, "--format", "json", "--output", reportPath})
+	require.NoError(t, err)
+	content, err = os.ReadFile(reportPath)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(content, &r))
+	assert.Equal(t, report.Summary{Total: 1, UpToDate: 1}, r.Summary)
+	assert.Empty(t, r.Actions[0].Patch)
+}
+
+// TestRecursiveUpdateConfig tests the README filename, discovery globs and placeholder defaults of the configuration file,
+// with a nested configuration file overriding the one of the repository
+func TestRecursiveUpdateConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, dir := range []string{"actions/build", "actions/deploy", "tools/lint"} {
+		actionDir := filepath.Join(tmpDir, dir)
+		require.NoError(t, os.MkdirAll(actionDir, 0755))
+		actionYML := "name: " + filepath.Base(dir) + "\ndescription: The action\ninputs:\n  token:\n    description: The token\n    required: true\n"
+		require.NoError(t, os.WriteFile(filepath.Join(actionDir, "action.yml"), []byte(actionYML), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(actionDir, "DOCS.md"), []byte("<!--inputs-->\n<!--/inputs-->\n"), 0644))
+	}
+	config := "readme: DOCS.md\ninclude: ['actions/**']\ndefaults:\n  inputs:\n    columns: name,required\n"
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".gh-action-readme.yml"), []byte(config), 0644))
+	nested := "defaults:\n  inputs:\n    columns: name,description\n"
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "actions", "deploy", ".gh-action-readme.yml"), []byte(nested), 0644))
+
+	originalWd, _ := os.Getwd()
+	defer func() { _ = os.Chdir(originalWd) }()
+	require.NoError(t, os.Chdir(tmpDir))
+
+	app := &cli.App{
+		Commands: []*cli.Command{update.NewCommand()},
+	}
+
+	err := app.Run([]string{"app", "update", "--recursive"})
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "actions", "build", "DOCS.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "| Name    | Required |")
+	content, err = os.ReadFile(filepath.Join(tmpDir, "actions", "deploy", "DOCS.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "| Name    | Description |")
+	// tools/lint isn't included
+	content, err = os.ReadFile(filepath.Join(tmpDir, "tools", "lint", "DOCS.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "<!--inputs-->\n<!--/inputs-->\n", string(content))
+	_, err = os.Stat(filepath.Join(tmpDir, "actions", "build", "README.md"))
+	assert.True(t, os.IsNotExist(err))
+}
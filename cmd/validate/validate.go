@@ -0,0 +1,81 @@
+package validate
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/reakaleek/gh-action-readme/internal/action"
+	"github.com/reakaleek/gh-action-readme/internal/helpers"
+	"github.com/urfave/cli/v2"
+)
+
+func NewCommand() *cli.Command {
+	var recursive bool
+	return &cli.Command{
+		Name:      "validate",
+		Usage:     "Validate action.yml against the metadata syntax for GitHub Actions",
+		ArgsUsage: "[action.yml...]",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:        "recursive",
+				Aliases:     []string{"r"},
+				Value:       false,
+				Destination: &recursive,
+				Usage:       "Search recursively for all action.yml/action.yaml files",
+			},
+		},
+		Action: func(ctx *cli.Context) error {
+			return validateRun(ctx.Args().Slice(), recursive)
+		},
+	}
+}
+
+func validateRun(actionFiles []string, recursive bool) error {
+	var err error
+	if len(actionFiles) == 0 && recursive {
+		actionFiles, err = helpers.FindAllActionFiles(".")
+		if err != nil {
+			return err
+		}
+		if len(actionFiles) == 0 {
+			return fmt.Errorf("no action.yml or action.yaml files found")
+		}
+		helpers.PrintHeader("Found %d action file(s)\n\n", len(actionFiles))
+	}
+	if len(actionFiles) == 0 {
+		actionPath, err := helpers.FindActionFile()
+		if err != nil {
+			return err
+		}
+		actionFiles = []string{actionPath}
+	}
+
+	valid := 0
+	invalid := 0
+
+	green := color.New(color.FgGreen).SprintFunc()
+	parser := action.NewParser()
+
+	for _, actionPath := range actionFiles {
+		violations, err := parser.Validate(actionPath)
+		if err != nil {
+			return fmt.Errorf("error validating %s: %w", actionPath, err)
+		}
+		helpers.PrintViolations(violations)
+		if action.Invalid(violations) {
+			invalid++
+		} else {
+			fmt.Printf("%s %s is valid\n", green("✓"), actionPath)
+			valid++
+		}
+	}
+
+	if len(actionFiles) > 1 {
+		helpers.PrintSummary(valid, "valid", color.FgGreen, invalid, "invalid", color.FgRed)
+	}
+
+	if invalid > 0 {
+		return cli.Exit("", 1)
+	}
+	return nil
+}
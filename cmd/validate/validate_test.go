@@ -0,0 +1,91 @@
+package validate_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/reakaleek/gh-action-readme/cmd/validate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+func runValidate(t *testing.T, args ...string) error {
+	t.Helper()
+	app := &cli.App{
+		Commands: []*cli.Command{validate.NewCommand()},
+		ExitErrHandler: func(_ *cli.Context, _ error) {
+			// keep the test process alive on cli.Exit
+		},
+	}
+	return app.Run(append([]string{"app", "validate"}, args...))
+}
+
+func TestValidateCommand_Valid(t *testing.T) {
+	tmpDir := t.TempDir()
+	actionPath := filepath.Join(tmpDir, "action.yml")
+	actionYML := `name: Test Action
+description: A test action
+inputs:
+  token:
+    description: 'A token'
+    required: true
+runs:
+  using: node20
+  main: index.js
+`
+	require.NoError(t, os.WriteFile(actionPath, []byte(actionYML), 0644))
+
+	err := runValidate(t, actionPath)
+	assert.NoError(t, err)
+}
+
+func TestValidateCommand_Invalid(t *testing.T) {
+	tmpDir := t.TempDir()
+	actionPath := filepath.Join(tmpDir, "action.yml")
+	actionYML := `name: Test Action
+description: A test action
+inputs:
+  token:
+    description: 'A token'
+    require: true
+runs:
+  using: node20
+  main: index.js
+`
+	require.NoError(t, os.WriteFile(actionPath, []byte(actionYML), 0644))
+
+	err := runValidate(t, actionPath)
+
+	var exitErr cli.ExitCoder
+	require.ErrorAs(t, err, &exitErr)
+	assert.Equal(t, 1, exitErr.ExitCode())
+}
+
+func TestValidateCommand_Recursive(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, dir := range []string{"valid", "invalid"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, dir), 0755))
+	}
+	require.NoError(t, os.WriteFile(
+		filepath.Join(tmpDir, "valid", "action.yml"),
+		[]byte("name: Valid\ndescription: Valid\nruns:\n  using: composite\n  steps: []\n"),
+		0644,
+	))
+	require.NoError(t, os.WriteFile(
+		filepath.Join(tmpDir, "invalid", "action.yml"),
+		[]byte("name: Invalid\nruns:\n  using: composite\n  steps: []\n"),
+		0644,
+	))
+
+	originalWd, _ := os.Getwd()
+	defer func() { _ = os.Chdir(originalWd) }()
+	require.NoError(t, os.Chdir(tmpDir))
+
+	err := runValidate(t, "--recursive")
+
+	var exitErr cli.ExitCoder
+	require.ErrorAs(t, err, &exitErr)
+	assert.Equal(t, 1, exitErr.ExitCode())
+}
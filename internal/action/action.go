@@ -5,6 +5,7 @@ import (
 	"gopkg.in/yaml.v3"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 )
 
 // https://docs.github.com/en/actions/creating-actions/metadata-syntax-for-github-actions
@@ -17,6 +18,13 @@ type Action struct {
 	InputsOrder  []string
 	Outputs      Outputs
 	OutputsOrder []string
+	Secrets      Secrets
+	SecretsOrder []string
+	Runs         Runs
+	Branding     Branding
+	// Positions are the positions of the inputs, of the values of the outputs and of the values of the composite steps
+	// in the action file by their path, e.g. "inputs.token", "outputs.version.value" or "runs.steps[0].with.token"
+	Positions map[string]Position `yaml:"-"`
 }
 
 func New(
@@ -39,41 +47,128 @@ func New(
 	}
 }
 
-func codeBlock(code string) string {
+// CodeSpan renders the code as inline code.
+// The code is fenced by more backticks than it contains in a row, so backticks in the code don't end the code span.
+// Example: "a`b" is fenced by two backticks on each side
+func CodeSpan(code string) string {
 	if strings.TrimSpace(code) == "" {
 		return "` `"
 	}
-	return fmt.Sprintf("`%s`", code)
+	longest, run := 0, 0
+	for _, r := range code {
+		if r == '`' {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	fence := strings.Repeat("`", longest+1)
+	// A space is stripped on both sides of the code, so it can start or end with a backtick
+	if strings.HasPrefix(code, "`") || strings.HasSuffix(code, "`") {
+		code = " " + code + " "
+	}
+	return fence + code + fence
 }
 
 func (a *Action) GetInputsMatrix() [][]string {
+	matrix, _ := a.GetInputsMatrixFor(a.InputsOrder, a.DefaultInputColumns(), nil)
+	return matrix
+}
+
+// DefaultInputColumns returns the columns of the inputs table if no columns are configured
+func (a *Action) DefaultInputColumns() []string {
+	// Only reusable workflow inputs have a type
+	if a.hasTypedInputs() {
+		return []string{ColumnName, ColumnDescription, ColumnType, ColumnRequired, ColumnDefault}
+	}
+	return []string{ColumnName, ColumnDescription, ColumnRequired, ColumnDefault}
+}
+
+func (a *Action) hasTypedInputs() bool {
+	for _, input := range a.Inputs {
+		if input.Type != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *Action) GetOutputsMatrix() [][]string {
+	matrix, _ := a.GetOutputsMatrixFor(a.OutputsOrder, DefaultOutputColumns, nil)
+	return matrix
+}
+
+// GetRunsMatrix describes the runs block as property/value rows
+func (a *Action) GetRunsMatrix() [][]string {
 	var matrix [][]string
-	matrix = append(matrix, []string{"Name", "Description", "Required", "Default"})
-	for _, key := range a.InputsOrder {
-		input := a.Inputs[key]
-		matrix = append(
-			matrix,
-			[]string{
-				codeBlock(key),
-				input.Description,
-				codeBlock(strconv.FormatBool(input.Required)),
-				codeBlock(input.Default),
-			},
-		)
+	matrix = append(matrix, []string{"Property", "Value"})
+	r := a.Runs
+	if r.Using == "" {
+		return matrix
+	}
+	matrix = append(matrix, []string{"Type", r.Kind()})
+	matrix = append(matrix, []string{"Using", CodeSpan(r.Using)})
+	switch {
+	case r.IsNode():
+		matrix = append(matrix, []string{"Main", CodeSpan(r.Main)})
+		if r.Pre != "" {
+			matrix = append(matrix, []string{"Pre", hook(r.Pre, r.PreIf)})
+		}
+		if r.Post != "" {
+			matrix = append(matrix, []string{"Post", hook(r.Post, r.PostIf)})
+		}
+	case r.IsDocker():
+		matrix = append(matrix, []string{"Image", CodeSpan(r.Image)})
+		if r.Entrypoint != "" {
+			matrix = append(matrix, []string{"Entrypoint", CodeSpan(r.Entrypoint)})
+		}
+		if len(r.Args) > 0 {
+			args := make([]string, len(r.Args))
+			for i, arg := range r.Args {
+				args[i] = CodeSpan(arg)
+			}
+			matrix = append(matrix, []string{"Args", strings.Join(args, " ")})
+		}
+		if r.PreEntrypoint != "" {
+			matrix = append(matrix, []string{"Pre", hook(r.PreEntrypoint, r.PreIf)})
+		}
+		if r.PostEntrypoint != "" {
+			matrix = append(matrix, []string{"Post", hook(r.PostEntrypoint, r.PostIf)})
+		}
+	case r.IsComposite():
+		matrix = append(matrix, []string{"Steps", strconv.Itoa(len(r.Steps))})
+		var uses []string
+		for _, step := range r.Steps {
+			if step.Uses != "" {
+				uses = append(uses, CodeSpan(step.Uses))
+			}
+		}
+		if len(uses) > 0 {
+			matrix = append(matrix, []string{"Uses", strings.Join(uses, "\n")})
+		}
 	}
 	return matrix
 }
 
-func (a *Action) GetOutputsMatrix() [][]string {
+func hook(entrypoint string, condition string) string {
+	if condition == "" {
+		return CodeSpan(entrypoint)
+	}
+	return fmt.Sprintf("%s (if: %s)", CodeSpan(entrypoint), CodeSpan(condition))
+}
+
+func (a *Action) GetSecretsMatrix() [][]string {
 	var matrix [][]string
-	matrix = append(matrix, []string{"Name", "Description"})
-	for _, key := range a.OutputsOrder {
-		output := a.Outputs[key]
+	matrix = append(matrix, []string{"Name", "Description", "Required"})
+	for _, key := range a.SecretsOrder {
+		secret := a.Secrets[key]
 		matrix = append(
 			matrix,
 			[]string{
-				codeBlock(key),
-				output.Description,
+				CodeSpan(key),
+				secret.Description,
+				CodeSpan(strconv.FormatBool(secret.Required)),
 			},
 		)
 	}
@@ -81,20 +176,143 @@ func (a *Action) GetOutputsMatrix() [][]string {
 }
 
 type Input struct {
-	Description string
-	Required    bool
-	Default     string
+	Description        string
+	Required           bool
+	Default            string
+	DeprecationMessage string `yaml:"deprecationMessage"`
+	// Type is only set for reusable workflow inputs (boolean, number or string)
+	Type string
+	// Example is not part of the metadata syntax, it is only used for the example column
+	Example string
+}
+
+func (i Input) IsDeprecated() bool {
+	return i.DeprecationMessage != ""
+}
+
+// GetDescription returns the description, prefixed with the deprecation message if the input is deprecated
+func (i Input) GetDescription() string {
+	if !i.IsDeprecated() {
+		return i.Description
+	}
+	deprecation := fmt.Sprintf("**Deprecated:** %s", strings.TrimSpace(i.DeprecationMessage))
+	if i.Description == "" {
+		return deprecation
+	}
+	return deprecation + "\n" + i.Description
 }
 
 type ActionNodes struct {
 	Inputs  yaml.Node `yaml:"inputs,omitempty"`
 	Outputs yaml.Node `yaml:"outputs,omitempty"`
+	Runs    yaml.Node `yaml:"runs,omitempty"`
+}
+
+// Position is the line and column of a key or value in the action file, both are 0 if unknown
+type Position struct {
+	Line   int
+	Column int
+	// literal is set for the values of literal block scalars, whose lines are the lines of the file
+	literal bool
+}
+
+// after returns the position of the character that follows text at the start of the value.
+// Positions in multi-line values are only known for literal block scalars, others return the start of the value.
+func (p Position) after(text string) Position {
+	i := strings.LastIndex(text, "\n")
+	switch {
+	case p.Line == 0:
+		return p
+	case i == -1:
+		return Position{Line: p.Line, Column: p.Column + utf8.RuneCountInString(text), literal: p.literal}
+	case p.literal:
+		return Position{Line: p.Line + strings.Count(text, "\n"), Column: p.Column + utf8.RuneCountInString(text[i+1:]), literal: true}
+	default:
+		return p
+	}
 }
 
 type Inputs = map[string]Input
 
 type Output struct {
 	Description string
+	Value       string
 }
 
 type Outputs = map[string]Output
+
+type Secret struct {
+	Description string
+	Required    bool
+}
+
+type Secrets = map[string]Secret
+
+// Branding is the icon and color of the action on GitHub Marketplace
+type Branding struct {
+	Icon  string
+	Color string
+}
+
+// Runs is the runs block of an action.
+// Only the keys relevant to the given runs.using value are set.
+type Runs struct {
+	Using          string
+	Main           string
+	Pre            string
+	PreIf          string `yaml:"pre-if"`
+	Post           string
+	PostIf         string `yaml:"post-if"`
+	Image          string
+	Entrypoint     string
+	PreEntrypoint  string `yaml:"pre-entrypoint"`
+	PostEntrypoint string `yaml:"post-entrypoint"`
+	Args           []string
+	Env            map[string]string
+	Steps          []Step
+}
+
+// IsNode reports whether the action is a JavaScript action (node12, node16, node20, ...)
+func (r Runs) IsNode() bool {
+	return strings.HasPrefix(r.Using, "node")
+}
+
+func (r Runs) IsDocker() bool {
+	return r.Using == "docker"
+}
+
+func (r Runs) IsComposite() bool {
+	return r.Using == "composite"
+}
+
+// Kind returns a human-readable description of the action type
+// Example: "JavaScript (Node.js 20)"
+func (r Runs) Kind() string {
+	switch {
+	case r.IsNode():
+		return fmt.Sprintf("JavaScript (Node.js %s)", strings.TrimPrefix(r.Using, "node"))
+	case r.IsDocker():
+		if strings.HasPrefix(r.Image, "docker://") {
+			return "Docker (pre-built image)"
+		}
+		return "Docker (Dockerfile)"
+	case r.IsComposite():
+		return "Composite"
+	default:
+		return r.Using
+	}
+}
+
+// Step is a single step of a composite action
+type Step struct {
+	ID               string
+	Name             string
+	If               string
+	Uses             string
+	Run              string
+	Shell            string
+	With             map[string]string
+	Env              map[string]string
+	WorkingDirectory string `yaml:"working-directory"`
+	ContinueOnError  string `yaml:"continue-on-error"`
+}
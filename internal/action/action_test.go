@@ -38,3 +38,188 @@ func TestAction_GetInputsMarkdown(t *testing.T) {
 	)
 
 }
+
+func TestAction_GetRunsMatrixNode(t *testing.T) {
+	// arrange
+	a := Action{
+		Runs: Runs{
+			Using: "node20",
+			Main:  "dist/index.js",
+			Pre:   "dist/setup.js",
+			PreIf: "runner.os == 'Linux'",
+			Post:  "dist/cleanup.js",
+		},
+	}
+
+	// act
+	matrix := a.GetRunsMatrix()
+
+	// assert
+	assert.Equal(t,
+		[][]string{
+			{"Property", "Value"},
+			{"Type", "JavaScript (Node.js 20)"},
+			{"Using", "`node20`"},
+			{"Main", "`dist/index.js`"},
+			{"Pre", "`dist/setup.js` (if: `runner.os == 'Linux'`)"},
+			{"Post", "`dist/cleanup.js`"},
+		},
+		matrix,
+	)
+}
+
+func TestAction_GetRunsMatrixDocker(t *testing.T) {
+	// arrange
+	a := Action{
+		Runs: Runs{
+			Using:          "docker",
+			Image:          "docker://alpine:3.20",
+			Args:           []string{"--verbose", "${{ inputs.path }}"},
+			PostEntrypoint: "/cleanup.sh",
+		},
+	}
+
+	// act
+	matrix := a.GetRunsMatrix()
+
+	// assert
+	assert.Equal(t,
+		[][]string{
+			{"Property", "Value"},
+			{"Type", "Docker (pre-built image)"},
+			{"Using", "`docker`"},
+			{"Image", "`docker://alpine:3.20`"},
+			{"Args", "`--verbose` `${{ inputs.path }}`"},
+			{"Post", "`/cleanup.sh`"},
+		},
+		matrix,
+	)
+}
+
+func TestAction_GetRunsMatrixComposite(t *testing.T) {
+	// arrange
+	a := Action{
+		Runs: Runs{
+			Using: "composite",
+			Steps: []Step{
+				{Uses: "actions/checkout@v4"},
+				{Run: "make test", Shell: "bash"},
+				{Uses: "actions/setup-go@v5"},
+			},
+		},
+	}
+
+	// act
+	matrix := a.GetRunsMatrix()
+
+	// assert
+	assert.Equal(t,
+		[][]string{
+			{"Property", "Value"},
+			{"Type", "Composite"},
+			{"Using", "`composite`"},
+			{"Steps", "3"},
+			{"Uses", "`actions/checkout@v4`\n`actions/setup-go@v5`"},
+		},
+		matrix,
+	)
+}
+
+func TestAction_GetRunsMatrixEmpty(t *testing.T) {
+	// arrange
+	a := Action{}
+
+	// act
+	matrix := a.GetRunsMatrix()
+
+	// assert
+	assert.Equal(t, [][]string{{"Property", "Value"}}, matrix)
+}
+
+func TestAction_GetInputsMatrixTyped(t *testing.T) {
+	// arrange
+	a := Action{
+		Inputs: Inputs{
+			"publish": {
+				Description: "publish description.",
+				Required:    true,
+				Type:        "boolean",
+			},
+		},
+		InputsOrder: []string{"publish"},
+	}
+
+	// act
+	matrix := a.GetInputsMatrix()
+
+	// assert
+	assert.Equal(t,
+		[][]string{
+			{"Name", "Description", "Type", "Required", "Default"},
+			{"`publish`", "publish description.", "`boolean`", "`true`", "` `"},
+		},
+		matrix,
+	)
+}
+
+func TestAction_GetSecretsMatrix(t *testing.T) {
+	// arrange
+	a := Action{
+		Secrets: Secrets{
+			"token": {
+				Description: "token description.",
+				Required:    true,
+			},
+		},
+		SecretsOrder: []string{"token"},
+	}
+
+	// act
+	matrix := a.GetSecretsMatrix()
+
+	// assert
+	assert.Equal(t,
+		[][]string{
+			{"Name", "Description", "Required"},
+			{"`token`", "token description.", "`true`"},
+		},
+		matrix,
+	)
+}
+
+func TestAction_GetInputsMatrixDeprecated(t *testing.T) {
+	// arrange
+	a := Action{
+		Inputs: Inputs{
+			"input1": {
+				Description:        "input1 description.",
+				DeprecationMessage: "Use input2 instead.",
+			},
+			"input2": {
+				Description: "input2 description.",
+			},
+		},
+		InputsOrder: []string{"input1", "input2"},
+	}
+
+	// act
+	matrix := a.GetInputsMatrix()
+
+	// assert
+	assert.Equal(t,
+		[][]string{
+			{"Name", "Description", "Required", "Default"},
+			{"`input1`", "**Deprecated:** Use input2 instead.\ninput1 description.", "`false`", "` `"},
+			{"`input2`", "input2 description.", "`false`", "` `"},
+		},
+		matrix,
+	)
+}
+
+func TestCodeSpan(t *testing.T) {
+	assert.Equal(t, "`main`", CodeSpan("main"))
+	assert.Equal(t, "` `", CodeSpan(""))
+	assert.Equal(t, "``a`b``", CodeSpan("a`b"))
+	assert.Equal(t, "``` ``x`` ```", CodeSpan("``x``"))
+	assert.Equal(t, "`` ` ``", CodeSpan("`"))
+}
@@ -0,0 +1,124 @@
+package action
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Columns of the inputs and outputs tables
+const (
+	ColumnName        = "name"
+	ColumnDescription = "description"
+	ColumnRequired    = "required"
+	ColumnDefault     = "default"
+	ColumnType        = "type"
+	ColumnDeprecated  = "deprecated"
+	ColumnExample     = "example"
+	ColumnValue       = "value"
+)
+
+// DefaultOutputColumns are the columns of the outputs table if no columns are configured
+var DefaultOutputColumns = []string{ColumnName, ColumnDescription}
+
+type column[T any] struct {
+	label string
+	cell  func(name string, item T, columns []string) string
+}
+
+var inputColumns = map[string]column[Input]{
+	ColumnName: {"Name", func(name string, _ Input, _ []string) string {
+		return CodeSpan(name)
+	}},
+	ColumnDescription: {"Description", func(_ string, input Input, columns []string) string {
+		// The deprecation message has its own column
+		if contains(columns, ColumnDeprecated) {
+			return input.Description
+		}
+		return input.GetDescription()
+	}},
+	ColumnRequired: {"Required", func(_ string, input Input, _ []string) string {
+		return CodeSpan(strconv.FormatBool(input.Required))
+	}},
+	ColumnDefault: {"Default", func(_ string, input Input, _ []string) string {
+		return CodeSpan(input.Default)
+	}},
+	ColumnType: {"Type", func(_ string, input Input, _ []string) string {
+		return CodeSpan(input.Type)
+	}},
+	ColumnDeprecated: {"Deprecated", func(_ string, input Input, _ []string) string {
+		return strings.TrimSpace(input.DeprecationMessage)
+	}},
+	ColumnExample: {"Example", func(_ string, input Input, _ []string) string {
+		if input.Example == "" {
+			return ""
+		}
+		return CodeSpan(input.Example)
+	}},
+}
+
+var outputColumns = map[string]column[Output]{
+	ColumnName: {"Name", func(name string, _ Output, _ []string) string {
+		return CodeSpan(name)
+	}},
+	ColumnDescription: {"Description", func(_ string, output Output, _ []string) string {
+		return output.Description
+	}},
+	ColumnValue: {"Value", func(_ string, output Output, _ []string) string {
+		return CodeSpan(output.Value)
+	}},
+}
+
+// GetInputsMatrixFor returns the inputs table with a row for each of the given inputs
+// and the given columns in the given order.
+// The header label of a column can be overridden with labels, keyed by column.
+func (a *Action) GetInputsMatrixFor(keys []string, columns []string, labels map[string]string) ([][]string, error) {
+	return matrixFor(inputColumns, "inputs", columns, labels, keys, a.Inputs)
+}
+
+// GetOutputsMatrixFor returns the outputs table with a row for each of the given outputs
+// and the given columns in the given order.
+// The header label of a column can be overridden with labels, keyed by column.
+func (a *Action) GetOutputsMatrixFor(keys []string, columns []string, labels map[string]string) ([][]string, error) {
+	return matrixFor(outputColumns, "outputs", columns, labels, keys, a.Outputs)
+}
+
+func matrixFor[T any](
+	available map[string]column[T],
+	kind string,
+	columns []string,
+	labels map[string]string,
+	order []string,
+	items map[string]T,
+) ([][]string, error) {
+	header := make([]string, len(columns))
+	for i, key := range columns {
+		c, ok := available[key]
+		if !ok {
+			return nil, fmt.Errorf("unknown %s column %q, expected one of %s", kind, key, strings.Join(columnNames(available), ", "))
+		}
+		header[i] = c.label
+		if label, ok := labels[key]; ok {
+			header[i] = label
+		}
+	}
+	matrix := [][]string{header}
+	for _, name := range order {
+		row := make([]string, len(columns))
+		for i, key := range columns {
+			row[i] = available[key].cell(name, items[name], columns)
+		}
+		matrix = append(matrix, row)
+	}
+	return matrix, nil
+}
+
+func columnNames[T any](available map[string]column[T]) []string {
+	var names []string
+	for _, key := range []string{ColumnName, ColumnDescription, ColumnType, ColumnRequired, ColumnDefault, ColumnDeprecated, ColumnExample, ColumnValue} {
+		if _, ok := available[key]; ok {
+			names = append(names, key)
+		}
+	}
+	return names
+}
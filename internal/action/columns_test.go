@@ -0,0 +1,79 @@
+package action
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestAction_GetInputsMatrixFor(t *testing.T) {
+	// arrange
+	a := Action{
+		Inputs: Inputs{
+			"input1": {
+				Description:        "input1 description.",
+				Default:            "default1",
+				DeprecationMessage: "Use input2 instead.",
+			},
+			"input2": {
+				Description: "input2 description.",
+				Example:     "example2",
+			},
+		},
+		InputsOrder: []string{"input1", "input2"},
+	}
+
+	// act
+	matrix, err := a.GetInputsMatrixFor(
+		a.InputsOrder,
+		[]string{ColumnName, ColumnDefault, ColumnDescription, ColumnDeprecated, ColumnExample},
+		map[string]string{ColumnName: "Input", ColumnDefault: "Default value"},
+	)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t,
+		[][]string{
+			{"Input", "Default value", "Description", "Deprecated", "Example"},
+			{"`input1`", "`default1`", "input1 description.", "Use input2 instead.", ""},
+			{"`input2`", "` `", "input2 description.", "", "`example2`"},
+		},
+		matrix,
+	)
+}
+
+func TestAction_GetInputsMatrixForUnknownColumn(t *testing.T) {
+	// arrange
+	a := Action{}
+
+	// act
+	_, err := a.GetInputsMatrixFor(a.InputsOrder, []string{ColumnName, "defualt"}, nil)
+
+	// assert
+	assert.EqualError(t, err, `unknown inputs column "defualt", expected one of name, description, type, required, default, deprecated, example`)
+}
+
+func TestAction_GetOutputsMatrixFor(t *testing.T) {
+	// arrange
+	a := Action{
+		Outputs: Outputs{
+			"output1": {
+				Description: "output1 description.",
+				Value:       "${{ steps.run.outputs.result }}",
+			},
+		},
+		OutputsOrder: []string{"output1"},
+	}
+
+	// act
+	matrix, err := a.GetOutputsMatrixFor(a.OutputsOrder, []string{ColumnName, ColumnValue}, nil)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t,
+		[][]string{
+			{"Name", "Value"},
+			{"`output1`", "`${{ steps.run.outputs.result }}`"},
+		},
+		matrix,
+	)
+}
@@ -0,0 +1,219 @@
+package action
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	expressionPattern     = regexp.MustCompile(`(?s)\$\{\{(.*?)\}\}`)
+	inputReferencePattern = regexp.MustCompile(`(?:^|[^.\w-])inputs\s*(?:\.\s*([A-Za-z_][A-Za-z0-9_-]*)|\[\s*['"]([^'"]+)['"]\s*\])`)
+)
+
+// LintIssue is a problem with the usage of inputs in the steps or outputs of a composite action
+type LintIssue struct {
+	// Step is the step or output of the issue, e.g. "runs.steps[0] (Setup)" or "outputs.version".
+	// It is empty for issues that are not related to a single step (e.g. unused inputs)
+	Step    string
+	Input   string
+	Message string
+	// Line and Column are the position of the reference or key of the issue, both are 0 if unknown.
+	// Issues of LintInputs are positioned in the action file, issues of LintWith in the YAML of the with block.
+	Line   int
+	Column int
+}
+
+func (i LintIssue) String() string {
+	if i.Step == "" {
+		return i.Message
+	}
+	return fmt.Sprintf("%s: %s", i.Step, i.Message)
+}
+
+// Violation returns the issue as a violation of the action file, so it is printed with its line and column
+func (i LintIssue) Violation(file string) Violation {
+	return Violation{File: file, Line: i.Line, Column: i.Column, Message: i.String()}
+}
+
+// LintInputs cross-checks the declared inputs against the ${{ inputs.* }} references in the
+// run, with, env, if and working-directory values of the composite steps and in the values of the outputs.
+// It reports references to undeclared (or misspelled) inputs and declared inputs that are never used.
+// Only composite actions are linted, the result is empty for all other actions.
+func (a *Action) LintInputs() []LintIssue {
+	if !a.Runs.IsComposite() {
+		return nil
+	}
+	// Context property names are case-insensitive
+	declared := make(map[string]string)
+	for _, key := range a.InputsOrder {
+		declared[strings.ToLower(key)] = key
+	}
+	used := make(map[string]bool)
+	var issues []LintIssue
+	// check reports the references to undeclared inputs of a step or output and marks the declared ones as used
+	check := func(location string, references []reference) {
+		reported := make(map[string]bool)
+		for _, reference := range references {
+			if key, ok := declared[strings.ToLower(reference.name)]; ok {
+				used[key] = true
+				continue
+			}
+			if reported[reference.name] {
+				continue
+			}
+			reported[reference.name] = true
+			message := fmt.Sprintf("inputs.%s is not declared", reference.name)
+			if suggestion := closest(reference.name, a.InputsOrder); suggestion != "" {
+				message += fmt.Sprintf(", did you mean %q?", suggestion)
+			}
+			issues = append(issues, LintIssue{
+				Step:    location,
+				Input:   reference.name,
+				Message: message,
+				Line:    reference.position.Line,
+				Column:  reference.position.Column,
+			})
+		}
+	}
+	for i, step := range a.Runs.Steps {
+		stepName := fmt.Sprintf("runs.steps[%d]", i)
+		if step.Name != "" {
+			stepName += fmt.Sprintf(" (%s)", step.Name)
+		} else if step.ID != "" {
+			stepName += fmt.Sprintf(" (%s)", step.ID)
+		}
+		check(stepName, a.stepInputReferences(i, step))
+	}
+	for _, key := range a.OutputsOrder {
+		path := fmt.Sprintf("outputs.%s", key)
+		check(path, inputReferences(a.Outputs[key].Value, a.Positions[path+".value"]))
+	}
+	for _, key := range a.InputsOrder {
+		if !used[key] {
+			position := a.Positions["inputs."+key]
+			issues = append(issues, LintIssue{
+				Input:   key,
+				Message: fmt.Sprintf("input %q is declared but never used", key),
+				Line:    position.Line,
+				Column:  position.Column,
+			})
+		}
+	}
+	return issues
+}
+
+// reference is an input referenced in a value of the action
+type reference struct {
+	name     string
+	position Position
+}
+
+// stepInputReferences returns all inputs referenced by the i-th step, in order of appearance
+func (a *Action) stepInputReferences(i int, step Step) []reference {
+	var references []reference
+	at := func(key string) Position {
+		return a.Positions[fmt.Sprintf("runs.steps[%d].%s", i, key)]
+	}
+	// if is always evaluated as an expression, with or without ${{ }}
+	if step.If != "" {
+		if expressionPattern.MatchString(step.If) {
+			references = append(references, inputReferences(step.If, at("if"))...)
+		} else {
+			references = append(references, inputReferencesInExpression(step.If, at("if"))...)
+		}
+	}
+	references = append(references, inputReferences(step.Run, at("run"))...)
+	references = append(references, inputReferences(step.WorkingDirectory, at("working-directory"))...)
+	for _, key := range sortedKeys(step.With) {
+		references = append(references, inputReferences(step.With[key], at("with."+key))...)
+	}
+	for _, key := range sortedKeys(step.Env) {
+		references = append(references, inputReferences(step.Env[key], at("env."+key))...)
+	}
+	return references
+}
+
+// inputReferences returns the inputs referenced in all ${{ }} expressions of value, which starts at the given position
+func inputReferences(value string, at Position) []reference {
+	var references []reference
+	for _, match := range expressionPattern.FindAllStringSubmatchIndex(value, -1) {
+		expression := value[match[2]:match[3]]
+		references = append(references, inputReferencesInExpression(expression, at.after(value[:match[2]]))...)
+	}
+	return references
+}
+
+// inputReferencesInExpression returns the inputs referenced in the expression, which starts at the given position.
+// The position of a reference is the position of its inputs context.
+func inputReferencesInExpression(expression string, at Position) []reference {
+	var references []reference
+	for _, match := range inputReferencePattern.FindAllStringSubmatchIndex(expression, -1) {
+		start := match[0] + strings.Index(expression[match[0]:match[1]], "inputs")
+		var name string
+		if match[2] != -1 {
+			name = expression[match[2]:match[3]]
+		} else {
+			name = expression[match[4]:match[5]]
+		}
+		references = append(references, reference{name: name, position: at.after(expression[:start])})
+	}
+	return references
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// LintWith checks the keys of the with block of a step that uses the action, with is nil if the step has none.
+// It reports unknown (or misspelled) inputs, deprecated inputs and required inputs without a default that are missing.
+// Issues are positioned at their key, missing inputs have no position.
+func (a *Action) LintWith(with *yaml.Node) []LintIssue {
+	declared := make(map[string]string)
+	for _, key := range a.InputsOrder {
+		declared[strings.ToLower(key)] = key
+	}
+	set := make(map[string]bool)
+	var issues []LintIssue
+	var keys []*yaml.Node
+	if with != nil {
+		eachKey(with, func(key *yaml.Node, _ *yaml.Node) {
+			keys = append(keys, key)
+		})
+	}
+	for _, key := range keys {
+		name, ok := declared[strings.ToLower(key.Value)]
+		if !ok {
+			message := fmt.Sprintf("input %q is not declared", key.Value)
+			if suggestion := closest(key.Value, a.InputsOrder); suggestion != "" {
+				message += fmt.Sprintf(", did you mean %q?", suggestion)
+			}
+			issues = append(issues, LintIssue{Input: key.Value, Message: message, Line: key.Line, Column: key.Column})
+			continue
+		}
+		set[name] = true
+		if input := a.Inputs[name]; input.IsDeprecated() {
+			issues = append(issues, LintIssue{
+				Input:   key.Value,
+				Message: fmt.Sprintf("input %q is deprecated: %s", key.Value, strings.TrimSpace(input.DeprecationMessage)),
+				Line:    key.Line,
+				Column:  key.Column,
+			})
+		}
+	}
+	for _, key := range a.InputsOrder {
+		input := a.Inputs[key]
+		if input.Required && input.Default == "" && !set[key] {
+			issues = append(issues, LintIssue{Message: fmt.Sprintf("required input %q is missing", key)})
+		}
+	}
+	return issues
+}
@@ -0,0 +1,90 @@
+package action_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/reakaleek/gh-action-readme/internal/action"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestLintInputs(t *testing.T) {
+	// arrange
+	a, err := action.NewParser().Parse(filepath.Join("..", "testdata", "lint-action.yml"))
+	require.NoError(t, err)
+
+	// act
+	issues := a.LintInputs()
+
+	// assert
+	var messages []string
+	for _, issue := range issues {
+		messages = append(messages, fmt.Sprintf("%d:%d: %s", issue.Line, issue.Column, issue))
+	}
+	assert.Equal(t, []string{
+		`29:25: runs.steps[0] (Setup): inputs.go-verison is not declared, did you mean "go-version"?`,
+		`37:20: runs.steps[1] (Build): inputs.debug is not declared`,
+		`41:29: runs.steps[2] (Test): inputs.coverage is not declared`,
+		`43:30: runs.steps[2] (Test): inputs.root is not declared`,
+		`21:16: outputs.version: inputs.go_version is not declared, did you mean "go-version"?`,
+		`9:3: input "unused" is declared but never used`,
+	}, messages)
+	assert.Equal(t, `action.yml:29:25: runs.steps[0] (Setup): inputs.go-verison is not declared, did you mean "go-version"?`, issues[0].Violation("action.yml").String())
+}
+
+func TestLintInputsNoIssues(t *testing.T) {
+	// arrange
+	a, err := action.NewParser().Parse(filepath.Join("..", "..", "action.yml"))
+	require.NoError(t, err)
+
+	// act
+	issues := a.LintInputs()
+
+	// assert
+	assert.Empty(t, issues)
+}
+
+func TestLintInputsIgnoresNonCompositeActions(t *testing.T) {
+	// arrange
+	a := action.Action{
+		Inputs:      action.Inputs{"unused": {Description: "unused"}},
+		InputsOrder: []string{"unused"},
+		Runs:        action.Runs{Using: "node20", Main: "index.js"},
+	}
+
+	// act
+	issues := a.LintInputs()
+
+	// assert
+	assert.Empty(t, issues)
+}
+
+func TestLintWith(t *testing.T) {
+	// arrange
+	a := action.Action{
+		Inputs: action.Inputs{
+			"token":        {Description: "token", Required: true},
+			"path":         {Description: "path", Required: true, Default: "."},
+			"github-token": {Description: "GitHub token", DeprecationMessage: "Use token instead."},
+			"retries":      {Description: "retries", Required: true},
+		},
+		InputsOrder: []string{"token", "path", "github-token", "retries"},
+	}
+
+	var with yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte("Token: abc\ngithub-token: abc\nretires: 3\ndebug: true\n"), &with))
+
+	// act
+	issues := a.LintWith(with.Content[0])
+
+	// assert
+	assert.Equal(t, []action.LintIssue{
+		{Input: "github-token", Message: `input "github-token" is deprecated: Use token instead.`, Line: 2, Column: 1},
+		{Input: "retires", Message: `input "retires" is not declared, did you mean "retries"?`, Line: 3, Column: 1},
+		{Input: "debug", Message: `input "debug" is not declared`, Line: 4, Column: 1},
+		{Message: `required input "retries" is missing`},
+	}, issues)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"gopkg.in/yaml.v3"
 	"os"
+	"strings"
 )
 
 type Parser struct {
@@ -15,6 +16,9 @@ func (a *Parser) Parse(path string) (Action, error) {
 	if err != nil {
 		return action, fmt.Errorf("failed to read file: %w", err)
 	}
+	if isWorkflow(yamlFile) {
+		return a.parseWorkflow(yamlFile)
+	}
 	err = yaml.Unmarshal(yamlFile, &action)
 	if err != nil {
 		return action, fmt.Errorf("failed to unmarshal yaml: %w", err)
@@ -40,9 +44,65 @@ func attachInputsAndOutputsOrder(action *Action, yamlFile []byte) error {
 	if err != nil {
 		return err
 	}
+	attachPositions(action, &actionNodes, yamlFile)
 	return nil
 }
 
+// attachPositions records the positions of the inputs, of the values of the outputs and of the values
+// of the composite steps, so lint issues can point at the line and column of a reference
+func attachPositions(action *Action, actionNodes *ActionNodes, yamlFile []byte) {
+	lines := strings.Split(string(yamlFile), "\n")
+	positions := make(map[string]Position)
+	eachKey(&actionNodes.Inputs, func(key *yaml.Node, _ *yaml.Node) {
+		positions["inputs."+key.Value] = Position{Line: key.Line, Column: key.Column}
+	})
+	eachKey(&actionNodes.Outputs, func(output *yaml.Node, value *yaml.Node) {
+		eachKey(value, func(key *yaml.Node, value *yaml.Node) {
+			positions[fmt.Sprintf("outputs.%s.%s", output.Value, key.Value)] = valuePosition(value, lines)
+		})
+	})
+	eachKey(&actionNodes.Runs, func(key *yaml.Node, steps *yaml.Node) {
+		if key.Value != "steps" || steps.Kind != yaml.SequenceNode {
+			return
+		}
+		for i, step := range steps.Content {
+			eachKey(step, func(key *yaml.Node, value *yaml.Node) {
+				path := fmt.Sprintf("runs.steps[%d].%s", i, key.Value)
+				positions[path] = valuePosition(value, lines)
+				eachKey(value, func(key *yaml.Node, value *yaml.Node) {
+					positions[path+"."+key.Value] = valuePosition(value, lines)
+				})
+			})
+		}
+	})
+	action.Positions = positions
+}
+
+// eachKey calls fn for every key and value of a mapping node
+func eachKey(node *yaml.Node, fn func(key *yaml.Node, value *yaml.Node)) {
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		fn(node.Content[i], node.Content[i+1])
+	}
+}
+
+// valuePosition returns the position of the first character of the value of a scalar node.
+// The value of a block scalar starts on the line after its indicator, e.g. run: |
+func valuePosition(node *yaml.Node, lines []string) Position {
+	switch {
+	case node.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 && node.Line < len(lines):
+		line := lines[node.Line]
+		column := len(line) - len(strings.TrimLeft(line, " ")) + 1
+		return Position{Line: node.Line + 1, Column: column, literal: node.Style&yaml.LiteralStyle != 0}
+	case node.Style&(yaml.SingleQuotedStyle|yaml.DoubleQuotedStyle) != 0:
+		return Position{Line: node.Line, Column: node.Column + 1}
+	default:
+		return Position{Line: node.Line, Column: node.Column}
+	}
+}
+
 func attachInputsOrder(action *Action, actionNodes *ActionNodes) error {
 	var err error
 	if actionNodes.Inputs.Content != nil {
@@ -3,6 +3,7 @@ package action_test
 import (
 	"github.com/reakaleek/gh-action-readme/internal/action"
 	"github.com/stretchr/testify/assert"
+	"os"
 	"path/filepath"
 	"testing"
 )
@@ -28,3 +29,115 @@ func Test(t *testing.T) {
 	assert.Contains(t, a.Outputs, "output1")
 	assert.Equal(t, "output1 description.", a.Outputs["output1"].Description)
 }
+
+func TestParseRuns(t *testing.T) {
+	// arrange
+	actionReader := action.NewParser()
+
+	// act
+	a, err := actionReader.Parse(filepath.Join("..", "testdata", "runs-action.yml"))
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "node20", a.Runs.Using)
+	assert.True(t, a.Runs.IsNode())
+	assert.Equal(t, "dist/index.js", a.Runs.Main)
+	assert.Equal(t, "dist/setup.js", a.Runs.Pre)
+	assert.Equal(t, "runner.os == 'Linux'", a.Runs.PreIf)
+	assert.Equal(t, "dist/cleanup.js", a.Runs.Post)
+}
+
+func TestParseBranding(t *testing.T) {
+	// arrange
+	actionReader := action.NewParser()
+
+	// act
+	a, err := actionReader.Parse(filepath.Join("..", "testdata", "2-action.yml"))
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, action.Branding{Icon: "package", Color: "blue"}, a.Branding)
+}
+
+func TestParseCompositeSteps(t *testing.T) {
+	// arrange
+	actionReader := action.NewParser()
+
+	// act
+	a, err := actionReader.Parse(filepath.Join("..", "testdata", "1-action.yml"))
+
+	// assert
+	assert.NoError(t, err)
+	assert.True(t, a.Runs.IsComposite())
+	assert.Len(t, a.Runs.Steps, 1)
+	assert.Equal(t, "Step 1", a.Runs.Steps[0].Name)
+	assert.Equal(t, "bash", a.Runs.Steps[0].Shell)
+}
+
+func TestParseReusableWorkflow(t *testing.T) {
+	// arrange
+	actionReader := action.NewParser()
+
+	// act
+	a, err := actionReader.Parse(filepath.Join("..", "testdata", "workflow-action.yml"))
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "Build", a.Name)
+	assert.Equal(t, []string{"go-version", "publish"}, a.InputsOrder)
+	assert.Equal(t, "string", a.Inputs["go-version"].Type)
+	assert.Equal(t, "1.24", a.Inputs["go-version"].Default)
+	assert.Equal(t, "boolean", a.Inputs["publish"].Type)
+	assert.True(t, a.Inputs["publish"].Required)
+	assert.Equal(t, []string{"artifact-url"}, a.OutputsOrder)
+	assert.Equal(t, "${{ jobs.build.outputs.url }}", a.Outputs["artifact-url"].Value)
+	assert.Equal(t, []string{"registry-token", "slack-webhook"}, a.SecretsOrder)
+	assert.True(t, a.Secrets["registry-token"].Required)
+	assert.False(t, a.Secrets["slack-webhook"].Required)
+}
+
+func TestParseWorkflowWithoutWorkflowCall(t *testing.T) {
+	// arrange
+	path := filepath.Join(t.TempDir(), "ci.yml")
+	content := "name: CI\non: push\njobs:\n  test:\n    runs-on: ubuntu-latest\n"
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	actionReader := action.NewParser()
+
+	// act
+	_, err := actionReader.Parse(path)
+
+	// assert
+	assert.ErrorContains(t, err, "workflow has no workflow_call trigger")
+	assert.False(t, action.IsReusableWorkflow(path))
+}
+
+func TestParseWorkflowCallWithoutConfiguration(t *testing.T) {
+	// arrange
+	path := filepath.Join(t.TempDir(), "reusable.yml")
+	content := "name: Reusable\non: [push, workflow_call]\njobs:\n  test:\n    runs-on: ubuntu-latest\n"
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	actionReader := action.NewParser()
+
+	// act
+	a, err := actionReader.Parse(path)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "Reusable", a.Name)
+	assert.Empty(t, a.InputsOrder)
+	assert.True(t, action.IsReusableWorkflow(path))
+}
+
+func TestParseInputExample(t *testing.T) {
+	// arrange
+	path := filepath.Join(t.TempDir(), "action.yml")
+	content := "name: Test\ninputs:\n  path:\n    description: The path\n    example: ./dist\n"
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+	// act
+	a, err := action.NewParser().Parse(path)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "./dist", a.Inputs["path"].Example)
+}
@@ -0,0 +1,85 @@
+package action
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+// Sort orders of the rows of the inputs and outputs tables
+const (
+	SortDeclared      = "declared"
+	SortAlpha         = "alpha"
+	SortRequiredFirst = "required-first"
+)
+
+// Selection selects and orders the rows of the inputs and outputs tables
+type Selection struct {
+	// Sort is one of SortDeclared (default), SortAlpha or SortRequiredFirst
+	Sort string
+	// Include and Exclude are glob patterns matched against the names
+	Include []string
+	Exclude []string
+}
+
+// SelectInputs returns the names of the inputs matching the selection in the selected order
+func (a *Action) SelectInputs(selection Selection) ([]string, error) {
+	keys, err := filterKeys(a.InputsOrder, selection)
+	if err != nil {
+		return nil, err
+	}
+	switch selection.Sort {
+	case "", SortDeclared:
+	case SortAlpha:
+		sort.Strings(keys)
+	case SortRequiredFirst:
+		sort.SliceStable(keys, func(i, j int) bool {
+			return a.Inputs[keys[i]].Required && !a.Inputs[keys[j]].Required
+		})
+	default:
+		return nil, fmt.Errorf("unknown sort %q, expected one of %s, %s, %s", selection.Sort, SortDeclared, SortAlpha, SortRequiredFirst)
+	}
+	return keys, nil
+}
+
+// SelectOutputs returns the names of the outputs matching the selection in the selected order
+func (a *Action) SelectOutputs(selection Selection) ([]string, error) {
+	keys, err := filterKeys(a.OutputsOrder, selection)
+	if err != nil {
+		return nil, err
+	}
+	switch selection.Sort {
+	case "", SortDeclared:
+	case SortAlpha:
+		sort.Strings(keys)
+	default:
+		return nil, fmt.Errorf("unknown sort %q for outputs, expected one of %s, %s", selection.Sort, SortDeclared, SortAlpha)
+	}
+	return keys, nil
+}
+
+func filterKeys(order []string, selection Selection) ([]string, error) {
+	keys := []string{}
+	for _, key := range order {
+		included := len(selection.Include) == 0
+		for _, pattern := range selection.Include {
+			match, err := doublestar.Match(pattern, key)
+			if err != nil {
+				return nil, fmt.Errorf("invalid include pattern %q: %w", pattern, err)
+			}
+			included = included || match
+		}
+		for _, pattern := range selection.Exclude {
+			match, err := doublestar.Match(pattern, key)
+			if err != nil {
+				return nil, fmt.Errorf("invalid exclude pattern %q: %w", pattern, err)
+			}
+			included = included && !match
+		}
+		if included {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
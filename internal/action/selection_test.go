@@ -0,0 +1,77 @@
+package action
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func selectionTestAction() Action {
+	return Action{
+		Inputs: Inputs{
+			"token":      {Required: true},
+			"cache-key":  {},
+			"cache-path": {Required: true},
+			"debug":      {},
+		},
+		InputsOrder: []string{"token", "cache-key", "cache-path", "debug"},
+		Outputs: Outputs{
+			"result":    {},
+			"cache-hit": {},
+		},
+		OutputsOrder: []string{"result", "cache-hit"},
+	}
+}
+
+func TestAction_SelectInputs(t *testing.T) {
+	tests := []struct {
+		name      string
+		selection Selection
+		expected  []string
+	}{
+		{"declared", Selection{}, []string{"token", "cache-key", "cache-path", "debug"}},
+		{"alpha", Selection{Sort: SortAlpha}, []string{"cache-key", "cache-path", "debug", "token"}},
+		{"required-first", Selection{Sort: SortRequiredFirst}, []string{"token", "cache-path", "cache-key", "debug"}},
+		{"include", Selection{Include: []string{"cache-*"}}, []string{"cache-key", "cache-path"}},
+		{"exclude", Selection{Exclude: []string{"cache-*", "debug"}}, []string{"token"}},
+		{"include and exclude", Selection{Include: []string{"cache-*"}, Exclude: []string{"*-key"}}, []string{"cache-path"}},
+		{"no match", Selection{Include: []string{"foo"}}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// arrange
+			a := selectionTestAction()
+
+			// act
+			keys, err := a.SelectInputs(tt.selection)
+
+			// assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, keys)
+		})
+	}
+}
+
+func TestAction_SelectInputsUnknownSort(t *testing.T) {
+	// arrange
+	a := selectionTestAction()
+
+	// act
+	_, err := a.SelectInputs(Selection{Sort: "random"})
+
+	// assert
+	assert.EqualError(t, err, `unknown sort "random", expected one of declared, alpha, required-first`)
+}
+
+func TestAction_SelectOutputs(t *testing.T) {
+	// arrange
+	a := selectionTestAction()
+
+	// act
+	keys, err := a.SelectOutputs(Selection{Sort: SortAlpha})
+	_, requiredFirstErr := a.SelectOutputs(Selection{Sort: SortRequiredFirst})
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"cache-hit", "result"}, keys)
+	assert.Error(t, requiredFirstErr)
+}
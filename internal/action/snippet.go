@@ -0,0 +1,55 @@
+package action
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// WorkflowSnippet returns a job of the workflow at path, or a single step of the job if step is set.
+// A job is returned as a mapping with the job id as key and a step as a sequence with the step as only item,
+// so both encode to valid workflow YAML. Steps are matched by their id or name.
+// Comments of the selected nodes are kept.
+func WorkflowSnippet(path string, job string, step string) (*yaml.Node, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	var workflow workflowFile
+	if err := yaml.Unmarshal(content, &workflow); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal yaml: %w", err)
+	}
+	jobKey, jobNode := mappingEntry(&workflow.Jobs, job)
+	if jobNode == nil {
+		return nil, fmt.Errorf("job %q not found in %s", job, path)
+	}
+	if step == "" {
+		return &yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{jobKey, jobNode}}, nil
+	}
+	_, steps := mappingEntry(jobNode, "steps")
+	if steps == nil || steps.Kind != yaml.SequenceNode {
+		return nil, fmt.Errorf("job %q in %s has no steps", job, path)
+	}
+	for _, s := range steps.Content {
+		for _, key := range []string{"id", "name"} {
+			if _, value := mappingEntry(s, key); value != nil && value.Value == step {
+				return &yaml.Node{Kind: yaml.SequenceNode, Content: []*yaml.Node{s}}, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("step %q not found in job %q of %s", step, job, path)
+}
+
+// mappingEntry returns the key and value nodes of the key in a mapping node, or nil if there is no such key
+func mappingEntry(node *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	if node.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i], node.Content[i+1]
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,71 @@
+package action_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/reakaleek/gh-action-readme/internal/action"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestWorkflowSnippet(t *testing.T) {
+	path := filepath.Join("..", "testdata", "example-workflow.yml")
+	tests := []struct {
+		name     string
+		job      string
+		step     string
+		expected string
+	}{
+		{
+			name: "job",
+			job:  "unit",
+			expected: "unit:\n" +
+				"    runs-on: ubuntu-latest\n" +
+				"    steps:\n" +
+				"        - run: go test ./...\n",
+		},
+		{
+			name: "step by id",
+			job:  "e2e",
+			step: "run-action",
+			expected: "# Use the action from this commit\n" +
+				"- id: run-action\n" +
+				"  name: Run action\n" +
+				"  uses: ./\n" +
+				"  with:\n" +
+				"    token: ${{ secrets.GITHUB_TOKEN }} # needed for the API\n",
+		},
+		{
+			name:     "step by name",
+			job:      "e2e",
+			step:     "Deploy",
+			expected: "- name: Deploy\n  uses: ./deploy\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// act
+			node, err := action.WorkflowSnippet(path, tt.job, tt.step)
+
+			// assert
+			assert.NoError(t, err)
+			out, err := yaml.Marshal(node)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, string(out))
+		})
+	}
+}
+
+func TestWorkflowSnippetNotFound(t *testing.T) {
+	// arrange
+	path := filepath.Join("..", "testdata", "example-workflow.yml")
+
+	// act
+	_, jobErr := action.WorkflowSnippet(path, "lint", "")
+	_, stepErr := action.WorkflowSnippet(path, "e2e", "missing")
+
+	// assert
+	assert.ErrorContains(t, jobErr, `job "lint" not found`)
+	assert.ErrorContains(t, stepErr, `step "missing" not found in job "e2e"`)
+}
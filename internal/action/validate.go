@@ -0,0 +1,374 @@
+package action
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"os"
+	"sort"
+	"strings"
+)
+
+// https://docs.github.com/en/actions/creating-actions/metadata-syntax-for-github-actions
+
+// Violation is a single violation of the metadata syntax in an action file
+type Violation struct {
+	File    string
+	Line    int
+	Column  int
+	Message string
+	// Note is set for keys outside the metadata syntax that GitHub ignores, they don't make the action invalid
+	Note bool
+}
+
+func (v Violation) String() string {
+	if v.Note {
+		return fmt.Sprintf("%s:%d:%d: note: %s", v.File, v.Line, v.Column, v.Message)
+	}
+	return fmt.Sprintf("%s:%d:%d: %s", v.File, v.Line, v.Column, v.Message)
+}
+
+// Invalid reports whether any of the violations makes the action invalid
+func Invalid(violations []Violation) bool {
+	for _, violation := range violations {
+		if !violation.Note {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	nodeRuntimes  = []string{"node12", "node16", "node20", "node24"}
+	brandingColor = []string{"white", "black", "yellow", "blue", "green", "orange", "red", "purple", "gray-dark"}
+)
+
+// Validate checks the action file at path against the metadata syntax for GitHub Actions.
+// It returns all violations found, the error is only set if the file cannot be read or parsed.
+func (a *Parser) Validate(path string) ([]Violation, error) {
+	yamlFile, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	if isWorkflow(yamlFile) {
+		return nil, fmt.Errorf("%s is a workflow, only action metadata files can be validated", path)
+	}
+	var document yaml.Node
+	err = yaml.Unmarshal(yamlFile, &document)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal yaml: %w", err)
+	}
+	v := &validator{file: path}
+	if len(document.Content) == 0 {
+		v.report(&document, "file is empty")
+		return v.violations, nil
+	}
+	v.validateAction(document.Content[0])
+	sort.SliceStable(v.violations, func(i, j int) bool {
+		if v.violations[i].Line != v.violations[j].Line {
+			return v.violations[i].Line < v.violations[j].Line
+		}
+		return v.violations[i].Column < v.violations[j].Column
+	})
+	return v.violations, nil
+}
+
+type validator struct {
+	file       string
+	violations []Violation
+}
+
+func (v *validator) report(node *yaml.Node, format string, args ...interface{}) {
+	v.violations = append(v.violations, Violation{
+		File:    v.file,
+		Line:    node.Line,
+		Column:  node.Column,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *validator) note(node *yaml.Node, format string, args ...interface{}) {
+	v.report(node, format, args...)
+	v.violations[len(v.violations)-1].Note = true
+}
+
+// mapping checks that node is a mapping that only has the allowed keys and all required keys.
+// It returns the value nodes by key.
+func (v *validator) mapping(node *yaml.Node, path string, allowed []string, required ...string) map[string]*yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		v.report(node, "%s must be a mapping", path)
+		return nil
+	}
+	values := make(map[string]*yaml.Node)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i]
+		if _, ok := values[key.Value]; ok {
+			v.report(key, "%s.%s is defined more than once", path, key.Value)
+		}
+		values[key.Value] = node.Content[i+1]
+		if allowed != nil && !contains(allowed, key.Value) {
+			message := fmt.Sprintf("unknown key %s.%s", path, key.Value)
+			if suggestion := closest(key.Value, allowed); suggestion != "" {
+				message += fmt.Sprintf(", did you mean %q?", suggestion)
+			}
+			v.report(key, "%s", message)
+		}
+	}
+	for _, key := range required {
+		if _, ok := values[key]; !ok {
+			v.report(node, "%s.%s is required", path, key)
+		}
+	}
+	return values
+}
+
+func (v *validator) scalar(node *yaml.Node, path string) bool {
+	if node.Kind != yaml.ScalarNode {
+		v.report(node, "%s must be a string", path)
+		return false
+	}
+	return true
+}
+
+func (v *validator) boolean(node *yaml.Node, path string) {
+	if node.Kind != yaml.ScalarNode || node.Tag != "!!bool" {
+		v.report(node, "%s must be a boolean (true or false), got %q", path, node.Value)
+	}
+}
+
+func (v *validator) enum(node *yaml.Node, path string, values []string) bool {
+	if !v.scalar(node, path) {
+		return false
+	}
+	if !contains(values, node.Value) {
+		v.report(node, "%s must be one of %s, got %q", path, strings.Join(values, ", "), node.Value)
+		return false
+	}
+	return true
+}
+
+func (v *validator) validateAction(node *yaml.Node) {
+	values := v.mapping(
+		node,
+		"action",
+		[]string{"name", "author", "description", "inputs", "outputs", "runs", "branding"},
+		"name", "description", "runs",
+	)
+	for _, key := range []string{"name", "author", "description"} {
+		if value, ok := values[key]; ok {
+			v.scalar(value, key)
+		}
+	}
+	using := ""
+	if runs, ok := values["runs"]; ok {
+		using = v.validateRuns(runs)
+	}
+	if inputs, ok := values["inputs"]; ok {
+		v.validateInputs(inputs)
+	}
+	if outputs, ok := values["outputs"]; ok {
+		v.validateOutputs(outputs, using == "composite")
+	}
+	if branding, ok := values["branding"]; ok {
+		v.validateBranding(branding)
+	}
+}
+
+func (v *validator) validateInputs(node *yaml.Node) {
+	if node.Kind != yaml.MappingNode {
+		v.report(node, "inputs must be a mapping")
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		path := "inputs." + node.Content[i].Value
+		// example isn't part of the metadata syntax, it is only read for the example column of the inputs table
+		input, key, example := withoutKey(node.Content[i+1], "example")
+		values := v.mapping(input, path, []string{"description", "required", "default", "deprecationMessage"}, "description")
+		if key != nil {
+			v.note(key, "%s.example is not part of the metadata syntax, GitHub ignores it", path)
+			v.scalar(example, path+".example")
+		}
+		for _, key := range []string{"description", "default", "deprecationMessage"} {
+			if value, ok := values[key]; ok {
+				v.scalar(value, path+"."+key)
+			}
+		}
+		if required, ok := values["required"]; ok {
+			v.boolean(required, path+".required")
+		}
+	}
+}
+
+func (v *validator) validateOutputs(node *yaml.Node, composite bool) {
+	if node.Kind != yaml.MappingNode {
+		v.report(node, "outputs must be a mapping")
+		return
+	}
+	var required []string
+	if composite {
+		required = []string{"description", "value"}
+	} else {
+		required = []string{"description"}
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		path := "outputs." + node.Content[i].Value
+		allowed := []string{"description"}
+		if composite {
+			allowed = append(allowed, "value")
+		}
+		values := v.mapping(node.Content[i+1], path, allowed, required...)
+		for _, value := range values {
+			v.scalar(value, path)
+		}
+	}
+}
+
+// validateRuns validates the runs block and returns the runs.using value
+func (v *validator) validateRuns(node *yaml.Node) string {
+	if node.Kind != yaml.MappingNode {
+		v.report(node, "runs must be a mapping")
+		return ""
+	}
+	var using *yaml.Node
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == "using" {
+			using = node.Content[i+1]
+		}
+	}
+	if using == nil {
+		v.report(node, "runs.using is required")
+		return ""
+	}
+	if !v.enum(using, "runs.using", append(append([]string{}, nodeRuntimes...), "docker", "composite")) {
+		return ""
+	}
+	switch {
+	case contains(nodeRuntimes, using.Value):
+		v.mapping(node, "runs", []string{"using", "main", "pre", "pre-if", "post", "post-if"}, "main")
+	case using.Value == "docker":
+		values := v.mapping(
+			node,
+			"runs",
+			[]string{"using", "image", "env", "entrypoint", "pre-entrypoint", "post-entrypoint", "pre-if", "post-if", "args"},
+			"image",
+		)
+		if env, ok := values["env"]; ok {
+			v.mapping(env, "runs.env", nil)
+		}
+		if args, ok := values["args"]; ok && args.Kind != yaml.SequenceNode {
+			v.report(args, "runs.args must be a sequence")
+		}
+	case using.Value == "composite":
+		values := v.mapping(node, "runs", []string{"using", "steps"}, "steps")
+		if steps, ok := values["steps"]; ok {
+			v.validateSteps(steps)
+		}
+	}
+	return using.Value
+}
+
+func (v *validator) validateSteps(node *yaml.Node) {
+	if node.Kind != yaml.SequenceNode {
+		v.report(node, "runs.steps must be a sequence")
+		return
+	}
+	for i, step := range node.Content {
+		path := fmt.Sprintf("runs.steps[%d]", i)
+		values := v.mapping(
+			step,
+			path,
+			[]string{"id", "name", "if", "uses", "run", "shell", "with", "env", "working-directory", "continue-on-error"},
+		)
+		if values == nil {
+			continue
+		}
+		_, hasRun := values["run"]
+		_, hasUses := values["uses"]
+		switch {
+		case hasRun && hasUses:
+			v.report(step, "%s must not have both run and uses", path)
+		case !hasRun && !hasUses:
+			v.report(step, "%s must have either run or uses", path)
+		case hasRun:
+			if _, ok := values["shell"]; !ok {
+				v.report(step, "%s.shell is required if run is set", path)
+			}
+		}
+		for _, key := range []string{"with", "env"} {
+			if value, ok := values[key]; ok {
+				v.mapping(value, path+"."+key, nil)
+			}
+		}
+	}
+}
+
+func (v *validator) validateBranding(node *yaml.Node) {
+	values := v.mapping(node, "branding", []string{"icon", "color"})
+	if icon, ok := values["icon"]; ok {
+		v.scalar(icon, "branding.icon")
+	}
+	if color, ok := values["color"]; ok {
+		v.enum(color, "branding.color", brandingColor)
+	}
+}
+
+// withoutKey returns a copy of the mapping node without the given key, and the key and value nodes of the key.
+// The key and value are nil if node isn't a mapping or doesn't have the key.
+func withoutKey(node *yaml.Node, key string) (*yaml.Node, *yaml.Node, *yaml.Node) {
+	if node.Kind != yaml.MappingNode {
+		return node, nil, nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			mapping := *node
+			mapping.Content = append(append([]*yaml.Node{}, node.Content[:i]...), node.Content[i+2:]...)
+			return &mapping, node.Content[i], node.Content[i+1]
+		}
+	}
+	return node, nil, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// closest returns the candidate that is most similar to value,
+// or an empty string if none of the candidates is similar enough
+func closest(value string, candidates []string) string {
+	best := ""
+	threshold := len(value)/3 + 1
+	bestDistance := threshold + 1
+	for _, candidate := range candidates {
+		distance := levenshtein(strings.ToLower(value), strings.ToLower(candidate))
+		if distance <= threshold && distance < bestDistance {
+			best = candidate
+			bestDistance = distance
+		}
+	}
+	return best
+}
+
+func levenshtein(a string, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
@@ -0,0 +1,122 @@
+package action_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/reakaleek/gh-action-readme/internal/action"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	// arrange
+	parser := action.NewParser()
+	path := filepath.Join("..", "testdata", "invalid-action.yml")
+
+	// act
+	violations, err := parser.Validate(path)
+
+	// assert
+	require.NoError(t, err)
+	var messages []string
+	for _, violation := range violations {
+		messages = append(messages, violation.String())
+	}
+	assert.Equal(t, []string{
+		path + `:6:5: unknown key inputs.token.require, did you mean "required"?`,
+		path + `:9:15: inputs.retries.required must be a boolean (true or false), got "yes"`,
+		path + `:10:5: unknown key inputs.retries.defualt, did you mean "default"?`,
+		path + `:15:10: runs.using must be one of node12, node16, node20, node24, docker, composite, got "node18"`,
+		path + `:18:10: branding.color must be one of white, black, yellow, blue, green, orange, red, purple, gray-dark, got "pink"`,
+	}, messages)
+}
+
+func TestValidateValidActions(t *testing.T) {
+	for _, name := range []string{"1-action.yml", "2-action.yml", "runs-action.yml"} {
+		t.Run(name, func(t *testing.T) {
+			// arrange
+			parser := action.NewParser()
+
+			// act
+			violations, err := parser.Validate(filepath.Join("..", "testdata", name))
+
+			// assert
+			require.NoError(t, err)
+			assert.Empty(t, violations)
+		})
+	}
+}
+
+func TestInvalid(t *testing.T) {
+	// arrange
+	note := action.Violation{Message: "inputs.token.example is not part of the metadata syntax, GitHub ignores it", Note: true}
+	violation := action.Violation{Message: "inputs.token.description is required"}
+
+	// act & assert
+	assert.False(t, action.Invalid(nil))
+	assert.False(t, action.Invalid([]action.Violation{note}))
+	assert.True(t, action.Invalid([]action.Violation{note, violation}))
+}
+
+func TestValidateRuns(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected []string
+	}{
+		{
+			"missing required keys",
+			"name: Test\nruns:\n  using: docker\n",
+			[]string{
+				"1:1: action.description is required",
+				"3:3: runs.image is required",
+			},
+		},
+		{
+			"composite step without shell",
+			"name: Test\ndescription: Test\noutputs:\n  result:\n    description: Result\nruns:\n  using: composite\n  steps:\n    - run: echo hello\n    - name: Nothing\n",
+			[]string{
+				"5:5: outputs.result.value is required",
+				"9:7: runs.steps[0].shell is required if run is set",
+				"10:7: runs.steps[1] must have either run or uses",
+			},
+		},
+		{
+			"input example",
+			"name: Test\ndescription: Test\ninputs:\n  token:\n    description: Token\n    example: ghp_123\n  tags:\n    description: Tags\n    example:\n      - latest\nruns:\n  using: composite\n  steps: []\n",
+			[]string{
+				"6:5: note: inputs.token.example is not part of the metadata syntax, GitHub ignores it",
+				"9:5: note: inputs.tags.example is not part of the metadata syntax, GitHub ignores it",
+				"10:7: inputs.tags.example must be a string",
+			},
+		},
+		{
+			"node keys in docker action",
+			"name: Test\ndescription: Test\nruns:\n  using: docker\n  image: Dockerfile\n  main: index.js\n",
+			[]string{
+				"6:3: unknown key runs.main",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// arrange
+			path := filepath.Join(t.TempDir(), "action.yml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0644))
+			parser := action.NewParser()
+
+			// act
+			violations, err := parser.Validate(path)
+
+			// assert
+			require.NoError(t, err)
+			var messages []string
+			for _, violation := range violations {
+				messages = append(messages, violation.String()[len(path)+1:])
+			}
+			assert.Equal(t, tt.expected, messages)
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package action
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"os"
+)
+
+// https://docs.github.com/en/actions/using-workflows/workflow-syntax-for-github-actions#onworkflow_call
+
+const workflowCallTrigger = "workflow_call"
+
+type workflowFile struct {
+	Name string
+	On   yaml.Node `yaml:"on"`
+	Jobs yaml.Node `yaml:"jobs"`
+}
+
+type workflowCall struct {
+	Inputs  Inputs
+	Outputs Outputs
+	Secrets Secrets
+}
+
+type workflowCallNodes struct {
+	Inputs  yaml.Node `yaml:"inputs,omitempty"`
+	Outputs yaml.Node `yaml:"outputs,omitempty"`
+	Secrets yaml.Node `yaml:"secrets,omitempty"`
+}
+
+// IsReusableWorkflow reports whether the file at path is a workflow
+// that can be called from other workflows (on.workflow_call)
+func IsReusableWorkflow(path string) bool {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	var workflow workflowFile
+	if err := yaml.Unmarshal(content, &workflow); err != nil {
+		return false
+	}
+	_, ok := findWorkflowCall(&workflow.On)
+	return ok
+}
+
+func isWorkflow(content []byte) bool {
+	var workflow workflowFile
+	if err := yaml.Unmarshal(content, &workflow); err != nil {
+		return false
+	}
+	return workflow.On.Kind != 0 && workflow.Jobs.Kind != 0
+}
+
+func (a *Parser) parseWorkflow(content []byte) (Action, error) {
+	var action Action
+	var workflow workflowFile
+	err := yaml.Unmarshal(content, &workflow)
+	if err != nil {
+		return action, fmt.Errorf("failed to unmarshal yaml: %w", err)
+	}
+	action.Name = workflow.Name
+	node, ok := findWorkflowCall(&workflow.On)
+	if !ok {
+		return action, fmt.Errorf("workflow has no %s trigger", workflowCallTrigger)
+	}
+	if node == nil {
+		// on: workflow_call or on: [workflow_call] without inputs, outputs or secrets
+		return action, nil
+	}
+	var call workflowCall
+	err = node.Decode(&call)
+	if err != nil {
+		return action, fmt.Errorf("failed to decode %s: %w", workflowCallTrigger, err)
+	}
+	action.Inputs = call.Inputs
+	action.Outputs = call.Outputs
+	action.Secrets = call.Secrets
+	var nodes workflowCallNodes
+	err = node.Decode(&nodes)
+	if err != nil {
+		return action, fmt.Errorf("failed to decode %s: %w", workflowCallTrigger, err)
+	}
+	if nodes.Inputs.Content != nil {
+		action.InputsOrder, err = getOrderedKeys(&nodes.Inputs)
+		if err != nil {
+			return action, fmt.Errorf("failed to parse inputs order: %w", err)
+		}
+	}
+	if nodes.Outputs.Content != nil {
+		action.OutputsOrder, err = getOrderedKeys(&nodes.Outputs)
+		if err != nil {
+			return action, fmt.Errorf("failed to parse outputs order: %w", err)
+		}
+	}
+	if nodes.Secrets.Content != nil {
+		action.SecretsOrder, err = getOrderedKeys(&nodes.Secrets)
+		if err != nil {
+			return action, fmt.Errorf("failed to parse secrets order: %w", err)
+		}
+	}
+	return action, nil
+}
+
+// findWorkflowCall returns the workflow_call node of the on: block.
+// The returned node is nil if the trigger is used without any configuration.
+func findWorkflowCall(on *yaml.Node) (*yaml.Node, bool) {
+	switch on.Kind {
+	case yaml.ScalarNode:
+		return nil, on.Value == workflowCallTrigger
+	case yaml.SequenceNode:
+		for _, trigger := range on.Content {
+			if trigger.Value == workflowCallTrigger {
+				return nil, true
+			}
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(on.Content); i += 2 {
+			if on.Content[i].Value == workflowCallTrigger {
+				value := on.Content[i+1]
+				if value.Kind != yaml.MappingNode {
+					return nil, true
+				}
+				return value, true
+			}
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,279 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/reakaleek/gh-action-readme/internal/markdown"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// FileName is the name of the configuration file
+	FileName = ".gh-action-readme.yml"
+	// DefaultReadme is the README filename if the configuration doesn't set one
+	DefaultReadme = "README.md"
+	// DefaultTemplate is the builtin README template of init
+	DefaultTemplate = "default"
+)
+
+var attributeNamePattern = regexp.MustCompile(`^[\w-]+$`)
+
+// Config is the configuration of a directory, merged from the configuration files of the directory and its parents
+type Config struct {
+	// Readme are the README filenames, the first one that exists is used
+	Readme []string
+	// Defaults are the default attributes of the placeholders by placeholder name
+	// Example: {"inputs": {"columns": "name,description"}}
+	Defaults map[string]map[string]string
+	// Env are the values of the environment variables referenced with env: in attributes
+	Env map[string]string
+	// Template is the README template of init, the builtin default template or the path of a file
+	Template string
+	// TemplateDirs are searched for the files of template placeholders
+	TemplateDirs []string
+	include      []glob
+	exclude      []glob
+}
+
+// glob is a pattern relative to the directory of the configuration file that declares it
+type glob struct {
+	dir     string
+	pattern string
+}
+
+// file is the content of a configuration file
+type file struct {
+	Readme    stringList                   `yaml:"readme"`
+	Include   []string                     `yaml:"include"`
+	Exclude   []string                     `yaml:"exclude"`
+	Defaults  map[string]map[string]string `yaml:"defaults"`
+	Env       map[string]string            `yaml:"env"`
+	Templates struct {
+		Init string   `yaml:"init"`
+		Dirs []string `yaml:"dirs"`
+	} `yaml:"templates"`
+}
+
+// stringList is a list of strings that can also be written as a single string
+// Example: readme: README.md or readme: [README.md, readme.md]
+type stringList []string
+
+func (l *stringList) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*l = stringList{node.Value}
+		return nil
+	}
+	var list []string
+	if err := node.Decode(&list); err != nil {
+		return err
+	}
+	*l = list
+	return nil
+}
+
+// Load returns the configuration of the directory. The configuration files are looked up from the directory
+// upwards and merged, the settings of a file override the settings of the files in its parent directories.
+// Lists are replaced and maps are merged by key.
+// Returns an empty configuration if there is no configuration file.
+func Load(dir string) (*Config, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for {
+		p := filepath.Join(dir, FileName)
+		if _, err := os.Stat(p); err == nil {
+			paths = append(paths, p)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	c := &Config{}
+	// The top-most file is applied first, so the nearest file wins
+	for i := len(paths) - 1; i >= 0; i-- {
+		f, err := readFile(paths[i])
+		if err != nil {
+			return nil, err
+		}
+		c.merge(f, filepath.Dir(paths[i]))
+	}
+	return c, nil
+}
+
+// readFile reads and validates a configuration file
+func readFile(p string) (file, error) {
+	var f file
+	content, err := os.ReadFile(p)
+	if err != nil {
+		return f, err
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&f); err != nil && !errors.Is(err, io.EOF) {
+		return f, fmt.Errorf("failed to parse %s: %w", p, err)
+	}
+	if err := f.validate(); err != nil {
+		return f, fmt.Errorf("invalid %s: %w", p, err)
+	}
+	return f, nil
+}
+
+func (f file) validate() error {
+	for _, name := range f.Readme {
+		if name == "" {
+			return fmt.Errorf("readme must not be empty")
+		}
+	}
+	for _, pattern := range slices.Concat(f.Include, f.Exclude) {
+		if !doublestar.ValidatePattern(pattern) {
+			return fmt.Errorf("invalid glob %q", pattern)
+		}
+	}
+	for placeholder, attributes := range f.Defaults {
+		if !markdown.IsPlaceholder(placeholder) {
+			return fmt.Errorf("unknown placeholder %q in defaults", placeholder)
+		}
+		for name, value := range attributes {
+			if !attributeNamePattern.MatchString(name) {
+				return fmt.Errorf("invalid attribute name %q in defaults of %s", name, placeholder)
+			}
+			if strings.Contains(value, `"`) {
+				return fmt.Errorf("attribute %s in defaults of %s must not contain double quotes", name, placeholder)
+			}
+		}
+	}
+	return nil
+}
+
+// merge applies the settings of the file in the given directory to the configuration
+func (c *Config) merge(f file, dir string) {
+	if f.Readme != nil {
+		c.Readme = f.Readme
+	}
+	if f.Include != nil {
+		c.include = globs(dir, f.Include)
+	}
+	if f.Exclude != nil {
+		c.exclude = globs(dir, f.Exclude)
+	}
+	for placeholder, attributes := range f.Defaults {
+		if c.Defaults == nil {
+			c.Defaults = make(map[string]map[string]string)
+		}
+		if c.Defaults[placeholder] == nil {
+			c.Defaults[placeholder] = make(map[string]string)
+		}
+		for name, value := range attributes {
+			c.Defaults[placeholder][name] = value
+		}
+	}
+	for name, value := range f.Env {
+		if c.Env == nil {
+			c.Env = make(map[string]string)
+		}
+		c.Env[name] = value
+	}
+	if f.Templates.Init != "" {
+		c.Template = f.Templates.Init
+		if c.Template != DefaultTemplate {
+			c.Template = resolve(dir, c.Template)
+		}
+	}
+	if f.Templates.Dirs != nil {
+		c.TemplateDirs = make([]string, len(f.Templates.Dirs))
+		for i, templateDir := range f.Templates.Dirs {
+			c.TemplateDirs[i] = resolve(dir, templateDir)
+		}
+	}
+}
+
+func globs(dir string, patterns []string) []glob {
+	result := make([]glob, len(patterns))
+	for i, pattern := range patterns {
+		result[i] = glob{dir: dir, pattern: strings.TrimPrefix(pattern, "./")}
+	}
+	return result
+}
+
+// resolve returns the path relative to the directory of the configuration file
+func resolve(dir string, p string) string {
+	if filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Join(dir, p)
+}
+
+// ReadmeFilename returns the first README filename that exists in the directory,
+// or the first filename if none exists yet
+func (c *Config) ReadmeFilename(dir string) string {
+	if len(c.Readme) == 0 {
+		return DefaultReadme
+	}
+	for _, name := range c.Readme {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return name
+		}
+	}
+	return c.Readme[0]
+}
+
+// IsReadme reports whether the filename is one of the README filenames
+func (c *Config) IsReadme(name string) bool {
+	if len(c.Readme) == 0 {
+		return name == DefaultReadme
+	}
+	return slices.Contains(c.Readme, name)
+}
+
+// Includes reports whether an action or workflow file is documented when searching recursively.
+// The globs match the path of the file or of its directory, relative to the configuration file that declares them.
+// Example: "actions/**" includes "actions/deploy/action.yml"
+func (c *Config) Includes(p string) bool {
+	p, err := filepath.Abs(p)
+	if err != nil {
+		return true
+	}
+	if len(c.include) > 0 && !matchAny(c.include, p) {
+		return false
+	}
+	return !matchAny(c.exclude, p)
+}
+
+func matchAny(globs []glob, p string) bool {
+	for _, g := range globs {
+		rel, err := filepath.Rel(g.dir, p)
+		if err != nil {
+			continue
+		}
+		rel = filepath.ToSlash(rel)
+		for _, candidate := range []string{rel, path.Dir(rel)} {
+			if match, _ := doublestar.Match(g.pattern, candidate); match {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Settings returns the settings of the READMEs in the directory of the configuration
+func (c *Config) Settings() markdown.Settings {
+	return markdown.Settings{
+		Defaults:     c.Defaults,
+		Env:          c.Env,
+		TemplateDirs: c.TemplateDirs,
+	}
+}
@@ -0,0 +1,153 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeConfig writes a configuration file with the given lines to the directory
+func writeConfig(t *testing.T, dir string, lines ...string) {
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, FileName), []byte(strings.Join(lines, "\n")+"\n"), 0644))
+}
+
+func TestLoad_NoConfigFile(t *testing.T) {
+	// arrange
+	dir := t.TempDir()
+
+	// act
+	c, err := Load(dir)
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, DefaultReadme, c.ReadmeFilename(dir))
+	assert.True(t, c.Includes(filepath.Join(dir, "action.yml")))
+	assert.Empty(t, c.Settings().Defaults)
+}
+
+func TestLoad_NestedConfigOverridesParent(t *testing.T) {
+	// arrange
+	root := t.TempDir()
+	writeConfig(t, root,
+		"readme: [README.md, readme.md]",
+		"defaults:",
+		"  inputs:",
+		"    columns: name,description",
+		"    sort: alpha",
+		"  toc:",
+		"    depth: 3",
+		"env:",
+		"  VERSION: v1",
+		"  REGISTRY: ghcr.io",
+		"templates:",
+		"  init: docs/readme.md",
+		"  dirs: [docs/templates]",
+	)
+	nested := filepath.Join(root, "actions")
+	writeConfig(t, nested,
+		"readme: DOCS.md",
+		"defaults:",
+		"  inputs:",
+		"    sort: required-first",
+		"env:",
+		"  VERSION: v2",
+	)
+
+	// act
+	c, err := Load(filepath.Join(nested, "deploy"))
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, []string{"DOCS.md"}, c.Readme)
+	assert.Equal(t, map[string]map[string]string{
+		"inputs": {"columns": "name,description", "sort": "required-first"},
+		"toc":    {"depth": "3"},
+	}, c.Defaults)
+	assert.Equal(t, map[string]string{"VERSION": "v2", "REGISTRY": "ghcr.io"}, c.Env)
+	assert.Equal(t, filepath.Join(root, "docs", "readme.md"), c.Template)
+	assert.Equal(t, []string{filepath.Join(root, "docs", "templates")}, c.TemplateDirs)
+}
+
+func TestLoad_InvalidConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		lines    []string
+		expected string
+	}{
+		{name: "unknown field", lines: []string{"readmes: README.md"}, expected: "field readmes not found"},
+		{name: "unknown placeholder", lines: []string{"defaults:", "  input:", "    sort: alpha"}, expected: `unknown placeholder "input" in defaults`},
+		{name: "quoted value", lines: []string{"defaults:", "  inputs:", "    columns: 'a\"b'"}, expected: "must not contain double quotes"},
+		{name: "invalid glob", lines: []string{"include: ['actions/[']"}, expected: `invalid glob "actions/["`},
+		{name: "empty readme", lines: []string{"readme: ''"}, expected: "readme must not be empty"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// arrange
+			dir := t.TempDir()
+			writeConfig(t, dir, tt.lines...)
+
+			// act
+			_, err := Load(dir)
+
+			// assert
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), filepath.Join(dir, FileName))
+			assert.Contains(t, err.Error(), tt.expected)
+		})
+	}
+}
+
+func TestReadmeFilename(t *testing.T) {
+	// arrange
+	dir := t.TempDir()
+	c := &Config{Readme: []string{"README.md", "readme.md"}}
+
+	// act & assert
+	assert.Equal(t, "README.md", c.ReadmeFilename(dir))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "readme.md"), nil, 0644))
+	assert.Equal(t, "readme.md", c.ReadmeFilename(dir))
+	assert.True(t, c.IsReadme("README.md"))
+	assert.False(t, c.IsReadme("CHANGELOG.md"))
+	assert.True(t, (&Config{}).IsReadme(DefaultReadme))
+}
+
+func TestIncludes(t *testing.T) {
+	// arrange
+	root := t.TempDir()
+	writeConfig(t, root,
+		"include: ['actions/**', '.github/workflows/*.yml']",
+		"exclude: [actions/experimental]",
+	)
+	nested := filepath.Join(root, "actions", "legacy")
+	writeConfig(t, nested, "exclude: ['**']")
+
+	tests := []struct {
+		path     string
+		expected bool
+	}{
+		{path: "actions/deploy/action.yml", expected: true},
+		{path: ".github/workflows/build.yml", expected: true},
+		{path: "action.yml", expected: false},
+		{path: "tools/lint/action.yml", expected: false},
+		{path: "actions/experimental/action.yml", expected: false},
+		{path: "actions/legacy/action.yml", expected: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			p := filepath.Join(root, filepath.FromSlash(tt.path))
+			c, err := Load(filepath.Dir(p))
+			require.NoError(t, err)
+
+			// act
+			included := c.Includes(p)
+
+			// assert
+			assert.Equal(t, tt.expected, included)
+		})
+	}
+}
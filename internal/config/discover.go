@@ -0,0 +1,124 @@
+package config
+
+import (
+	"path/filepath"
+
+	"github.com/reakaleek/gh-action-readme/internal/helpers"
+	"github.com/reakaleek/gh-action-readme/internal/markdown"
+)
+
+// LoadWithReadme loads the configuration of the directory.
+// A README filename given with the readme flag overrides the README filenames of the configuration.
+func LoadWithReadme(dir string, readmeFilename string) (*Config, error) {
+	c, err := Load(dir)
+	if err != nil {
+		return nil, err
+	}
+	if readmeFilename != "" {
+		c.Readme = []string{readmeFilename}
+	}
+	return c, nil
+}
+
+// FindActionFileOrReadme looks for action.yml or action.yaml in the current directory.
+// If there is no action file, it returns an empty path for a README that documents the actions of other
+// directories, so it can still be updated for the actions it references.
+func FindActionFileOrReadme(readmePath string) (string, error) {
+	actionPath, err := helpers.FindActionFile()
+	if err == nil {
+		return actionPath, nil
+	}
+	if referencesActions(readmePath) {
+		return "", nil
+	}
+	return "", err
+}
+
+// referencesActions reports whether the README exists and has an actions-index placeholder
+// or placeholders with a local action attribute
+func referencesActions(readmePath string) bool {
+	doc, err := markdown.NewDoc(readmePath)
+	return err == nil && doc.ReferencesActions()
+}
+
+// Target is an action or workflow file, the README documenting it and the configuration of its directory
+type Target struct {
+	// Action is empty for a README that only references actions in other directories
+	Action string
+	Readme string
+	Config *Config
+}
+
+// Discovery are the READMEs found by searching the current directory recursively
+type Discovery struct {
+	Actions   []Target
+	Workflows []Target
+	// Root is the README in the current directory if there is no action in the current directory
+	// and the README references the actions of other directories
+	Root *Target
+}
+
+// Discover finds the action files and documented reusable workflows in the current directory and its subdirectories
+// that the configuration of their directory includes, and the READMEs documenting them.
+// A README filename given with the readme flag overrides the README filenames of the configuration.
+func Discover(readmeFilename string) (*Discovery, error) {
+	actionFiles, err := helpers.FindAllActionFiles(".")
+	if err != nil {
+		return nil, err
+	}
+	workflowFiles, err := helpers.FindDocumentedWorkflowFiles(".")
+	if err != nil {
+		return nil, err
+	}
+	d := &Discovery{}
+	if d.Actions, err = targets(actionFiles, readmeFilename); err != nil {
+		return nil, err
+	}
+	if d.Workflows, err = targets(workflowFiles, readmeFilename); err != nil {
+		return nil, err
+	}
+	root, err := LoadWithReadme(".", readmeFilename)
+	if err != nil {
+		return nil, err
+	}
+	// An excluded action in the current directory still documents itself in the README, so it isn't a root README
+	if readmePath, ok := helpers.RootReadmePath(actionFiles, root.ReadmeFilename(".")); ok && referencesActions(readmePath) {
+		d.Root = &Target{Readme: readmePath, Config: root}
+	}
+	return d, nil
+}
+
+// targets returns the targets of the files that the configuration of their directory includes
+func targets(files []string, readmeFilename string) ([]Target, error) {
+	var result []Target
+	for _, path := range files {
+		dir := filepath.Dir(path)
+		c, err := LoadWithReadme(dir, readmeFilename)
+		if err != nil {
+			return nil, err
+		}
+		if !c.Includes(path) {
+			continue
+		}
+		result = append(result, Target{Action: path, Readme: helpers.ReadmePath(path, c.ReadmeFilename(dir)), Config: c})
+	}
+	return result, nil
+}
+
+// Targets returns the READMEs of the actions and workflows, followed by the root README
+func (d *Discovery) Targets() []Target {
+	result := append(append([]Target{}, d.Actions...), d.Workflows...)
+	if d.Root != nil {
+		result = append(result, *d.Root)
+	}
+	return result
+}
+
+// ActionFiles returns the paths of the action files
+func (d *Discovery) ActionFiles() []string {
+	paths := make([]string, len(d.Actions))
+	for i, t := range d.Actions {
+		paths[i] = t.Action
+	}
+	return paths
+}
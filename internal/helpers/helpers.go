@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+
+	"github.com/reakaleek/gh-action-readme/internal/action"
 )
 
 // FindActionFile looks for action.yml or action.yaml in the current directory
@@ -19,6 +22,19 @@ func FindActionFile() (string, error) {
 	return "", fmt.Errorf("neither action.yml nor action.yaml found in current directory")
 }
 
+// ParseActionFile parses the action file at the given path.
+// It returns nil without error if the path is empty, e.g. for a README that only documents actions in other directories.
+func ParseActionFile(path string) (*action.Action, error) {
+	if path == "" {
+		return nil, nil
+	}
+	a, err := action.NewParser().Parse(path)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
 // FindAllActionFiles recursively searches for all action.yml and action.yaml files
 // starting from the given root directory
 func FindAllActionFiles(root string) ([]string, error) {
@@ -43,3 +59,92 @@ func FindAllActionFiles(root string) ([]string, error) {
 
 	return actionFiles, err
 }
+
+// FindAllWorkflowFiles returns all reusable workflows (on: workflow_call)
+// in the .github/workflows directory of the given root directory
+func FindAllWorkflowFiles(root string) ([]string, error) {
+	var workflowFiles []string
+	for _, pattern := range []string{"*.yml", "*.yaml"} {
+		matches, err := filepath.Glob(filepath.Join(root, ".github", "workflows", pattern))
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range matches {
+			if action.IsReusableWorkflow(match) {
+				workflowFiles = append(workflowFiles, match)
+			}
+		}
+	}
+	return workflowFiles, nil
+}
+
+// WorkflowReadmePath returns the path of the markdown file that documents a workflow
+// Example: ".github/workflows/build.yml" -> ".github/workflows/build.md"
+func WorkflowReadmePath(workflowPath string) string {
+	return strings.TrimSuffix(workflowPath, filepath.Ext(workflowPath)) + ".md"
+}
+
+// ReadmePath returns the path of the README documenting the given action or workflow file
+// Example: "setup/action.yml" -> "setup/README.md", ".github/workflows/build.yml" -> ".github/workflows/build.md"
+func ReadmePath(sourcePath string, readmeFilename string) string {
+	name := filepath.Base(sourcePath)
+	if name == "action.yml" || name == "action.yaml" {
+		return filepath.Join(filepath.Dir(sourcePath), readmeFilename)
+	}
+	return WorkflowReadmePath(sourcePath)
+}
+
+// RootReadmePath returns the README in the current directory if it exists and isn't documenting an action
+// in the current directory already, so it can list or reference the actions in subdirectories.
+func RootReadmePath(actionFiles []string, readmeFilename string) (string, bool) {
+	for _, actionPath := range actionFiles {
+		if filepath.Dir(actionPath) == "." {
+			return "", false
+		}
+	}
+	if _, err := os.Stat(readmeFilename); err != nil {
+		return "", false
+	}
+	return readmeFilename, true
+}
+
+// FindDocumentedWorkflowFiles returns all reusable workflows that have
+// a markdown file next to them (see WorkflowReadmePath)
+func FindDocumentedWorkflowFiles(root string) ([]string, error) {
+	workflowFiles, err := FindAllWorkflowFiles(root)
+	if err != nil {
+		return nil, err
+	}
+	var documented []string
+	for _, workflowPath := range workflowFiles {
+		if _, err := os.Stat(WorkflowReadmePath(workflowPath)); err == nil {
+			documented = append(documented, workflowPath)
+		}
+	}
+	return documented, nil
+}
+
+// ValidateActionFiles validates the given action files and prints all violations.
+// It returns an error if any of the files has violations.
+func ValidateActionFiles(actionFiles []string) error {
+	parser := action.NewParser()
+	invalid := 0
+	for _, actionPath := range actionFiles {
+		if action.IsReusableWorkflow(actionPath) {
+			// The metadata syntax only applies to actions
+			continue
+		}
+		violations, err := parser.Validate(actionPath)
+		if err != nil {
+			return err
+		}
+		PrintViolations(violations)
+		if action.Invalid(violations) {
+			invalid++
+		}
+	}
+	if invalid > 0 {
+		return fmt.Errorf("%d action file(s) are invalid", invalid)
+	}
+	return nil
+}
@@ -1,7 +1,10 @@
 package helpers
 
 import (
+	"fmt"
+
 	"github.com/fatih/color"
+	"github.com/reakaleek/gh-action-readme/internal/action"
 )
 
 // PrintSummary prints a colored summary line
@@ -18,3 +21,27 @@ func PrintSummary(count1 int, label1 string, color1 color.Attribute, count2 int,
 func PrintHeader(format string, args ...interface{}) {
 	color.Cyan(format, args...)
 }
+
+// PrintViolations prints each violation on its own line, notes are printed as warnings
+// Example: "✗ action.yml:3:5: unknown key inputs.token.require, did you mean "required"?"
+func PrintViolations(violations []action.Violation) {
+	red := color.New(color.FgRed).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+	for _, violation := range violations {
+		if violation.Note {
+			fmt.Printf("%s %s\n", yellow("!"), violation)
+		} else {
+			fmt.Printf("%s %s\n", red("✗"), violation)
+		}
+	}
+}
+
+// PrintUsageIssues prints each usage issue as a warning on its own line
+// Example: "! README.md:12: input "tokn" is not declared, did you mean "token"?"
+// The issues are generic so this package doesn't depend on the markdown package, which uses the helpers to find actions.
+func PrintUsageIssues[T fmt.Stringer](issues []T) {
+	yellow := color.New(color.FgYellow).SprintFunc()
+	for _, issue := range issues {
+		fmt.Printf("%s %s\n", yellow("!"), issue)
+	}
+}
@@ -52,12 +52,66 @@ func Test(t *testing.T) {
 			"testdata/with-placeholders-README-out.md",
 			"v1.0.0",
 		},
+		{
+			"testdata/with-placeholders-action.yml",
+			"testdata/closed-sections-README-in.md",
+			"testdata/closed-sections-README-out.md",
+			"v1.0.0",
+		},
 		{
 			"testdata/codeblock-action.yml",
 			"testdata/codeblock-README-in.md",
 			"testdata/codeblock-README-out.md",
 			"v1.0.0",
 		},
+		{
+			"testdata/runs-action.yml",
+			"testdata/runs-README-in.md",
+			"testdata/runs-README-out.md",
+			"v1.0.0",
+		},
+		{
+			"testdata/workflow-action.yml",
+			"testdata/workflow-README-in.md",
+			"testdata/workflow-README-out.md",
+			"v1.0.0",
+		},
+		{
+			"testdata/deprecated-action.yml",
+			"testdata/deprecated-README-in.md",
+			"testdata/deprecated-README-out.md",
+			"v1.0.0",
+		},
+		{
+			"testdata/grouped-action.yml",
+			"testdata/grouped-README-in.md",
+			"testdata/grouped-README-out.md",
+			"v1.0.0",
+		},
+		{
+			"testdata/usage-example-action.yml",
+			"testdata/usage-example-README-in.md",
+			"testdata/usage-example-README-out.md",
+			"v1.0.0",
+		},
+		{
+			"testdata/table-width-action.yml",
+			"testdata/table-width-README-in.md",
+			"testdata/table-width-README-out.md",
+			"v1.0.0",
+		},
+		{
+			"testdata/table-pipes-action.yml",
+			"testdata/table-pipes-README-in.md",
+			"testdata/table-pipes-README-out.md",
+			"v1.0.0",
+		},
+		{
+			"testdata/table-backticks-action.yml",
+			"testdata/table-backticks-README-in.md",
+			"testdata/table-backticks-README-out.md",
+			"v1.0.0",
+		},
 	}
 
 	for _, tt := range tests {
@@ -68,7 +122,7 @@ func Test(t *testing.T) {
 			if err != nil {
 				t.Fatal(err)
 			}
-			doc, err := markdown.NewDoc(tt.readmePath)
+			doc, err := markdown.NewDocOrCreate(tt.readmePath)
 			if err != nil {
 				t.Fatal(err)
 			}
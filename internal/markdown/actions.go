@@ -0,0 +1,98 @@
+package markdown
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/reakaleek/gh-action-readme/internal/action"
+)
+
+// isLocalAction reports whether the action attribute of a placeholder is a path relative to the README
+// Example: <!--inputs action="./deploy"-->
+func isLocalAction(value string) bool {
+	return strings.HasPrefix(value, ".")
+}
+
+// actionFor returns the action a section documents: the action file referenced by the action attribute
+// of its start marker, or the given default action. It fails if there is neither.
+// The action attribute of usage placeholders is the uses reference, unless it is a local path.
+func (d *Doc) actionFor(s section, a *action.Action) (*action.Action, error) {
+	value, err := getAttribute(s.start.text, "action")
+	if err == nil && isLocalAction(value) {
+		return d.loadAction(filepath.Join(filepath.Dir(d.name), value))
+	}
+	if err == nil && s.start.name != usageSectionName && s.start.name != usageExampleSectionName {
+		return nil, fmt.Errorf("invalid action %q in %s placeholder, expected a path relative to the README (e.g. ./%s)", value, s.start.name, value)
+	}
+	if a == nil {
+		return nil, fmt.Errorf("no action to document in %s placeholder. add an action.yml next to the README or an action attribute, e.g. action=\"./deploy\"", s.start.name)
+	}
+	return a, nil
+}
+
+// ReferencesActions reports whether the document has placeholders that document the actions of other directories,
+// an actions-index placeholder or a placeholder with a local action attribute
+func (d *Doc) ReferencesActions() bool {
+	for _, m := range d.markers() {
+		if m.closing {
+			continue
+		}
+		if m.name == actionsIndexSectionName {
+			return true
+		}
+		if value, err := getAttribute(m.text, "action"); err == nil && isLocalAction(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// loadAction parses the action file at path, or the action.yml/action.yaml file if path is a directory.
+// Every action is parsed only once per document.
+func (d *Doc) loadAction(path string) (*action.Action, error) {
+	if a, ok := d.actions[path]; ok {
+		return a, nil
+	}
+	file := path
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		file = ""
+		for _, name := range []string{"action.yml", "action.yaml"} {
+			if _, err := os.Stat(filepath.Join(path, name)); err == nil {
+				file = filepath.Join(path, name)
+				break
+			}
+		}
+		if file == "" {
+			return nil, fmt.Errorf("no action.yml or action.yaml found in %s", path)
+		}
+	}
+	a, err := action.NewParser().Parse(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+	}
+	if d.actions == nil {
+		d.actions = make(map[string]*action.Action)
+	}
+	d.actions[path] = &a
+	return &a, nil
+}
+
+// updateSections replaces the content of the sections with the content rendered for the action they document
+func (d *Doc) updateSections(sections []section, a *action.Action, render func(s section, a *action.Action) (string, error)) error {
+	edits := make([]edit, 0, len(sections))
+	for _, s := range sections {
+		target, err := d.actionFor(s, a)
+		if err != nil {
+			return err
+		}
+		content, err := render(s, target)
+		if err != nil {
+			return err
+		}
+		edits = append(edits, d.sectionEdit(s, content))
+	}
+	d.apply(edits...)
+	return nil
+}
@@ -0,0 +1,131 @@
+package markdown
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/reakaleek/gh-action-readme/internal/action"
+	"github.com/reakaleek/gh-action-readme/internal/helpers"
+)
+
+const (
+	actionsIndexSortName = "name"
+	actionsIndexSortPath = "path"
+)
+
+// indexedAction is an action listed by the actions-index placeholder
+type indexedAction struct {
+	action *action.Action
+	// path is the directory of the action relative to the README, with forward slashes
+	path string
+}
+
+// updateActionsIndex renders a table of all actions in the directory of the README and its subdirectories
+// Example: <!--actions-index sort="path" path="actions/**"-->
+func (d *Doc) updateActionsIndex() error {
+	sections := d.sectionsNamed(actionsIndexSectionName)
+	if len(sections) == 0 {
+		return nil
+	}
+	actions, err := d.findActions()
+	if err != nil {
+		return err
+	}
+	edits := make([]edit, 0, len(sections))
+	for _, s := range sections {
+		content, err := d.actionsIndex(s, actions)
+		if err != nil {
+			return err
+		}
+		edits = append(edits, d.sectionEdit(s, content))
+	}
+	d.apply(edits...)
+	return nil
+}
+
+// findActions parses all actions below the directory of the README, except an action next to the README
+func (d *Doc) findActions() ([]indexedAction, error) {
+	root := filepath.Dir(d.name)
+	files, err := helpers.FindAllActionFiles(root)
+	if err != nil {
+		return nil, err
+	}
+	var actions []indexedAction
+	for _, file := range files {
+		rel, err := filepath.Rel(root, filepath.Dir(file))
+		if err != nil {
+			return nil, err
+		}
+		if rel == "." {
+			continue
+		}
+		a, err := d.loadAction(filepath.Dir(file))
+		if err != nil {
+			return nil, err
+		}
+		actions = append(actions, indexedAction{action: a, path: filepath.ToSlash(rel)})
+	}
+	return actions, nil
+}
+
+func (d *Doc) actionsIndex(s section, actions []indexedAction) (string, error) {
+	order := actionsIndexSortName
+	if value, err := getAttribute(s.start.text, "sort"); err == nil {
+		order = value
+	}
+	if order != actionsIndexSortName && order != actionsIndexSortPath {
+		return "", fmt.Errorf("invalid actions-index sort %q, expected %q or %q", order, actionsIndexSortName, actionsIndexSortPath)
+	}
+	pattern, _ := getAttribute(s.start.text, "path")
+	if pattern != "" && !doublestar.ValidatePattern(pattern) {
+		return "", fmt.Errorf("invalid actions-index path %q", pattern)
+	}
+
+	var selected []indexedAction
+	for _, a := range actions {
+		if pattern != "" {
+			if ok, _ := doublestar.Match(pattern, a.path); !ok {
+				continue
+			}
+		}
+		selected = append(selected, a)
+	}
+	sort.SliceStable(selected, func(i, j int) bool {
+		if order == actionsIndexSortName && selected[i].action.Name != selected[j].action.Name {
+			return strings.ToLower(selected[i].action.Name) < strings.ToLower(selected[j].action.Name)
+		}
+		return selected[i].path < selected[j].path
+	})
+
+	readmeFilename := filepath.Base(d.name)
+	matrix := [][]string{{"Name", "Description", "Path", "README"}}
+	for _, a := range selected {
+		readme := ""
+		if _, err := os.Stat(filepath.Join(filepath.Dir(d.name), filepath.FromSlash(a.path), readmeFilename)); err == nil {
+			readme = fmt.Sprintf("[%s](%s/%s)", readmeFilename, a.path, readmeFilename)
+		}
+		matrix = append(matrix, []string{
+			a.action.Name,
+			firstSentence(a.action.Description),
+			action.CodeSpan(a.path),
+			readme,
+		})
+	}
+	return table(matrix), nil
+}
+
+// firstSentence returns the description up to the end of its first sentence on a single line
+// Example: "Deploys the app. Supports rollbacks." -> "Deploys the app."
+func firstSentence(description string) string {
+	text := strings.Join(strings.Fields(description), " ")
+	for i := 0; i < len(text); i++ {
+		if (text[i] == '.' || text[i] == '!' || text[i] == '?') && (i+1 == len(text) || text[i+1] == ' ') {
+			return text[:i+1]
+		}
+	}
+	return text
+}
@@ -0,0 +1,131 @@
+package markdown
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// monorepo creates actions in the given directories of a temporary directory, with a README for each
+// action whose name starts with "a"
+func monorepo(t *testing.T, actions map[string]string) string {
+	root := t.TempDir()
+	for dir, name := range actions {
+		writeAction(t, filepath.Join(root, dir), "name: "+name+"\ndescription: |\n  The "+name+" action.\n  Second sentence.\n")
+		if name[0] == 'a' {
+			require.NoError(t, os.WriteFile(filepath.Join(root, dir, "README.md"), nil, 0644))
+		}
+	}
+	return root
+}
+
+func TestUpdateActionsIndex(t *testing.T) {
+	root := monorepo(t, map[string]string{
+		"deploy":         "alpha",
+		"actions/setup":  "charlie",
+		"actions/lint":   "bravo",
+		"tools/go/build": "Delta",
+	})
+	tests := []struct {
+		name      string
+		attribute string
+		expected  []string
+	}{
+		{
+			name: "sorted by name",
+			expected: []string{
+				"| Name    | Description         | Path             | README                        |",
+				"|---------|---------------------|------------------|-------------------------------|",
+				"| alpha   | The alpha action.   | `deploy`         | [README.md](deploy/README.md) |",
+				"| bravo   | The bravo action.   | `actions/lint`   |                               |",
+				"| charlie | The charlie action. | `actions/setup`  |                               |",
+				"| Delta   | The Delta action.   | `tools/go/build` |                               |",
+			},
+		},
+		{
+			name:      "sorted by path",
+			attribute: ` sort="path"`,
+			expected: []string{
+				"| Name    | Description         | Path             | README                        |",
+				"|---------|---------------------|------------------|-------------------------------|",
+				"| bravo   | The bravo action.   | `actions/lint`   |                               |",
+				"| charlie | The charlie action. | `actions/setup`  |                               |",
+				"| alpha   | The alpha action.   | `deploy`         | [README.md](deploy/README.md) |",
+				"| Delta   | The Delta action.   | `tools/go/build` |                               |",
+			},
+		},
+		{
+			name:      "filtered by path",
+			attribute: ` path="actions/*"`,
+			expected: []string{
+				"| Name    | Description         | Path            | README |",
+				"|---------|---------------------|-----------------|--------|",
+				"| bravo   | The bravo action.   | `actions/lint`  |        |",
+				"| charlie | The charlie action. | `actions/setup` |        |",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// arrange
+			start := "<!--actions-index" + tt.attribute + "-->"
+			doc := Doc{
+				name:   filepath.Join(root, "README.md"),
+				source: fromLines(start, "<!--/actions-index-->"),
+			}
+
+			// act
+			err := doc.updateActionsIndex()
+
+			// assert
+			assert.NoError(t, err)
+			lines := append([]string{start}, tt.expected...)
+			assert.Equal(t, string(fromLines(append(lines, "<!--/actions-index-->")...)), doc.ToString())
+		})
+	}
+}
+
+func TestUpdateActionsIndexSkipsOwnAction(t *testing.T) {
+	// arrange
+	root := monorepo(t, map[string]string{".": "root", "deploy": "deploy"})
+	doc := Doc{
+		name:   filepath.Join(root, "README.md"),
+		source: []byte("<!--actions-index-->\n<!--/actions-index-->"),
+	}
+
+	// act
+	err := doc.updateActionsIndex()
+
+	// assert
+	assert.NoError(t, err)
+	assert.NotContains(t, doc.ToString(), "root")
+	assert.Contains(t, doc.ToString(), "| deploy | The deploy action. | `deploy` |")
+}
+
+func TestUpdateActionsIndexInvalidAttributes(t *testing.T) {
+	root := monorepo(t, map[string]string{"deploy": "deploy"})
+	for _, source := range []string{
+		`<!--actions-index sort="size"-->`,
+		`<!--actions-index path="[a-"-->`,
+	} {
+		// arrange
+		doc := Doc{name: filepath.Join(root, "README.md"), source: []byte(source)}
+
+		// act
+		err := doc.updateActionsIndex()
+
+		// assert
+		assert.Error(t, err, source)
+	}
+}
+
+func TestFirstSentence(t *testing.T) {
+	assert.Equal(t, "Deploys the app.", firstSentence("Deploys the app. Supports rollbacks."))
+	assert.Equal(t, "Deploys the app to production.", firstSentence("Deploys the app\nto production.\nSupports rollbacks."))
+	assert.Equal(t, "Uses v1.2 of the CLI", firstSentence("Uses v1.2 of the CLI"))
+	assert.Equal(t, "Really?", firstSentence("Really? Yes."))
+	assert.Equal(t, "", firstSentence(""))
+}
@@ -0,0 +1,205 @@
+package markdown
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/reakaleek/gh-action-readme/internal/action"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeAction writes an action.yml with the given content to dir
+func writeAction(t *testing.T, dir string, content string) {
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "action.yml"), []byte(content), 0644))
+}
+
+func TestUpdateReferencedActions(t *testing.T) {
+	// arrange
+	root := t.TempDir()
+	writeAction(t, filepath.Join(root, "deploy"), "name: Deploy\ndescription: Deploys the app\ninputs:\n  environment:\n    description: Target environment\n")
+	writeAction(t, filepath.Join(root, "build"), "name: Build\ndescription: Builds the app\noutputs:\n  artifact:\n    description: Build artifact\n")
+	doc := Doc{
+		name: filepath.Join(root, "README.md"),
+		source: fromLines(
+			"# <!--name--><!--/name-->",
+			"## <!--name action=\"./deploy\"--><!--/name-->",
+			"<!--description action=\"./deploy\"-->",
+			"<!--/description-->",
+			"<!--inputs action=\"./deploy\" columns=\"name\"-->",
+			"<!--/inputs-->",
+			"## <!--name action=\"./build/action.yml\"--><!--/name-->",
+			"<!--description action=\"./build\"-->",
+			"<!--/description-->",
+			"<!--outputs action=\"./build\" columns=\"name\"-->",
+			"<!--/outputs-->",
+		),
+	}
+
+	// act
+	err := doc.Update(&action.Action{Name: "Monorepo"})
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, string(fromLines(
+		generatedComment,
+		"# <!--name-->Monorepo<!--/name-->",
+		"## <!--name action=\"./deploy\"-->Deploy<!--/name-->",
+		"<!--description action=\"./deploy\"-->",
+		"Deploys the app",
+		"<!--/description-->",
+		"<!--inputs action=\"./deploy\" columns=\"name\"-->",
+		"| Name          |",
+		"|---------------|",
+		"| `environment` |",
+		"<!--/inputs-->",
+		"## <!--name action=\"./build/action.yml\"-->Build<!--/name-->",
+		"<!--description action=\"./build\"-->",
+		"Builds the app",
+		"<!--/description-->",
+		"<!--outputs action=\"./build\" columns=\"name\"-->",
+		"| Name       |",
+		"|------------|",
+		"| `artifact` |",
+		"<!--/outputs-->",
+	)), doc.ToString())
+}
+
+func TestUpdateWithoutDefaultAction(t *testing.T) {
+	// arrange
+	root := t.TempDir()
+	writeAction(t, filepath.Join(root, "deploy"), "name: Deploy\n")
+	doc := Doc{
+		name: filepath.Join(root, "README.md"),
+		source: fromLines(
+			"## <!--name action=\"./deploy\"--><!--/name-->",
+			"<!--toc-->",
+			"<!--/toc-->",
+		),
+	}
+
+	// act
+	err := doc.Update(nil)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Contains(t, doc.ToString(), "## <!--name action=\"./deploy\"-->Deploy<!--/name-->")
+}
+
+func TestUpdateWithoutAnyAction(t *testing.T) {
+	// arrange
+	root := t.TempDir()
+	writeAction(t, filepath.Join(root, "deploy"), "name: Deploy\n")
+	doc := Doc{
+		name: filepath.Join(root, "README.md"),
+		source: fromLines(
+			"# <!--name--><!--/name-->",
+			"## <!--name action=\"./deploy\"--><!--/name-->",
+		),
+	}
+
+	// act
+	err := doc.Update(nil)
+
+	// assert
+	assert.EqualError(t, err, `no action to document in name placeholder. add an action.yml next to the README or an action attribute, e.g. action="./deploy"`)
+}
+
+func TestReferencesActions(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected bool
+	}{
+		{name: "actions-index", source: "<!--actions-index-->\n<!--/actions-index-->", expected: true},
+		{name: "local action", source: "<!--inputs action=\"./deploy\"-->\n<!--/inputs-->", expected: true},
+		{name: "uses reference", source: "<!--usage action=\"owner/repo\" version=\"v1\"-->\n<!--/usage-->", expected: false},
+		{name: "plain placeholders", source: "<!--name--><!--/name-->\n<!--inputs-->\n<!--/inputs-->", expected: false},
+		{name: "code block", source: "```\n<!--actions-index-->\n```", expected: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// arrange
+			doc := Doc{source: []byte(tt.source)}
+
+			// act
+			references := doc.ReferencesActions()
+
+			// assert
+			assert.Equal(t, tt.expected, references)
+		})
+	}
+}
+
+func TestUpdateReferencedActionErrors(t *testing.T) {
+	root := t.TempDir()
+	writeAction(t, filepath.Join(root, "deploy"), "name: Deploy\n")
+	tests := []struct {
+		name   string
+		source string
+		err    string
+	}{
+		{
+			name:   "missing action",
+			source: "<!--inputs action=\"./missing\"-->\n<!--/inputs-->",
+			err:    "failed to parse",
+		},
+		{
+			name:   "directory without action file",
+			source: "<!--inputs action=\".\"-->\n<!--/inputs-->",
+			err:    "no action.yml or action.yaml found",
+		},
+		{
+			name:   "not a path",
+			source: "<!--inputs action=\"deploy\"-->\n<!--/inputs-->",
+			err:    "expected a path relative to the README",
+		},
+		{
+			name:   "local usage without uses",
+			source: "<!--usage-example action=\"./deploy\" version=\"v1\"-->\n<!--/usage-example-->",
+			err:    "missing uses attribute",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// arrange
+			doc := Doc{name: filepath.Join(root, "README.md"), source: []byte(tt.source)}
+
+			// act
+			err := doc.Update(&action.Action{})
+
+			// assert
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestUpdateUsageExampleReferencedAction(t *testing.T) {
+	// arrange
+	root := t.TempDir()
+	writeAction(t, filepath.Join(root, "deploy"), "name: Deploy\ninputs:\n  environment:\n    required: true\n")
+	doc := Doc{
+		name: filepath.Join(root, "README.md"),
+		source: fromLines(
+			"<!--usage-example action=\"./deploy\" uses=\"owner/repo/deploy\" version=\"v2\"-->",
+			"<!--/usage-example-->",
+		),
+	}
+
+	// act
+	err := doc.updateUsageExamples(nil)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, string(fromLines(
+		"<!--usage-example action=\"./deploy\" uses=\"owner/repo/deploy\" version=\"v2\"-->",
+		"```yaml",
+		"- uses: owner/repo/deploy@v2",
+		"  with:",
+		"    environment: \"\"",
+		"```",
+		"<!--/usage-example-->",
+	)), doc.ToString())
+}
@@ -0,0 +1,24 @@
+package markdown
+
+import (
+	"strings"
+)
+
+// https://docs.github.com/en/get-started/writing-on-github/getting-started-with-writing-and-formatting-on-github/basic-writing-and-formatting-syntax#alerts
+
+const (
+	calloutWarning = "WARNING"
+)
+
+func callout(kind string, text string) string {
+	var sb strings.Builder
+	sb.WriteString("> [!" + kind + "]\n")
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		if strings.TrimSpace(line) == "" {
+			sb.WriteString(">\n")
+			continue
+		}
+		sb.WriteString("> " + line + "\n")
+	}
+	return sb.String()
+}
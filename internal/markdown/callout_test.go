@@ -0,0 +1,22 @@
+package markdown
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCallout(t *testing.T) {
+	// act
+	md := callout(calloutWarning, "The `old` input is deprecated.")
+
+	// assert
+	assert.Equal(t, "> [!WARNING]\n> The `old` input is deprecated.\n", md)
+}
+
+func TestCalloutMultiline(t *testing.T) {
+	// act
+	md := callout(calloutWarning, "First line.\n\nSecond paragraph.\n")
+
+	// assert
+	assert.Equal(t, "> [!WARNING]\n> First line.\n>\n> Second paragraph.\n", md)
+}
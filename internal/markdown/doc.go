@@ -5,9 +5,9 @@ import (
 	"fmt"
 	"github.com/bmatcuk/doublestar/v4"
 	"github.com/reakaleek/gh-action-readme/internal/action"
-	"github.com/sergi/go-diff/diffmatchpatch"
 	"os"
 	"regexp"
+	"slices"
 	"strings"
 )
 
@@ -17,13 +17,30 @@ const (
 	inputsSectionName          = "inputs"
 	outputsSectionName         = "outputs"
 	usageSectionName           = "usage"
+	usageExampleSectionName    = "usage-example"
+	runsSectionName            = "runs"
+	secretsSectionName         = "secrets"
 	tableOfContentsSectionName = "toc"
+	actionsIndexSectionName    = "actions-index"
+	templateSectionName        = "template"
+	includeSectionName         = "include"
+	exampleSectionName         = "example"
 	generatedComment           = "<!-- Generated by https://github.com/reakaleek/gh-action-readme -->"
 )
 
+// Doc is a markdown document with placeholder sections.
+// Placeholders are found in the HTML nodes of the parsed document and the content of their sections is
+// spliced into the source, everything outside the sections stays unchanged.
 type Doc struct {
-	name  string
-	lines []string
+	name     string
+	source   []byte
+	sections *sectionIndex
+	// actions are the actions referenced by the action attribute of the placeholders by their path
+	actions map[string]*action.Action
+	// settings are the project defaults of the placeholders
+	settings Settings
+	// autoClose adds the end markers that READMEs of the first releases leave out on update, see closeSections
+	autoClose bool
 }
 
 func NewDoc(name string) (*Doc, error) {
@@ -32,27 +49,27 @@ func NewDoc(name string) (*Doc, error) {
 		return nil, fmt.Errorf("failed to read file: %w", err)
 	}
 	return &Doc{
-		name:  name,
-		lines: strings.Split(content, "\n"),
+		name:   name,
+		source: []byte(content),
 	}, nil
 }
 
 // NewEmptyDoc creates an empty Doc with just the name (used for comparing against non-existent files)
 func NewEmptyDoc(name string) *Doc {
 	return &Doc{
-		name:  name,
-		lines: []string{},
+		name: name,
 	}
 }
 
-// NewDocOrCreate creates a new Doc from an existing file, or creates a new file with template content if it doesn't exist
+// NewDocOrCreate creates a new Doc from an existing file, or creates a new file with template content if it doesn't exist.
+// The name, description, inputs and outputs sections of the document are closed on update if their end marker is missing.
 func NewDocOrCreate(name string) (*Doc, error) {
 	content, err := readFile(name)
 	if errors.Is(err, os.ErrNotExist) {
 		// Create new file with template
 		newDoc := &Doc{
 			name: name,
-			lines: []string{
+			source: []byte(strings.Join([]string{
 				"# <!--name--><!--/name-->",
 				"<!--description-->",
 				"<!--/description-->",
@@ -74,7 +91,8 @@ func NewDocOrCreate(name string) (*Doc, error) {
 				"```",
 				"<!--/usage-->",
 				"",
-			},
+			}, "\n")),
+			autoClose: true,
 		}
 		return newDoc, nil
 	}
@@ -82,113 +100,142 @@ func NewDocOrCreate(name string) (*Doc, error) {
 		return nil, fmt.Errorf("failed to read file: %w", err)
 	}
 	return &Doc{
-		name:  name,
-		lines: strings.Split(content, "\n"),
+		name:      name,
+		source:    []byte(content),
+		autoClose: true,
 	}, nil
 }
 
-func (d *Doc) updateName(name string) {
-	d.updateAllSections(nameSectionName, name)
+func (d *Doc) updateName(a *action.Action) error {
+	return d.updateSections(d.sectionsNamed(nameSectionName), a, func(_ section, a *action.Action) (string, error) {
+		return a.Name, nil
+	})
 }
 
-func (d *Doc) updateAllSections(sectionName string, content string) {
-	// Find all section pairs ONCE, then process them
-	startIndices := d.findAllIndices(startCommentPattern(sectionName))
-	endIndices := d.findAllIndices(endCommentPattern(sectionName))
-	
-	if len(startIndices) != len(endIndices) {
-		return
-	}
-	
-	// Process in reverse order to avoid index shifting issues
-	for i := len(startIndices) - 1; i >= 0; i-- {
-		startIndex := startIndices[i]
-		endIndex := endIndices[i]
-		
-		if startIndex == endIndex {
-			// Single-line format: <!--name--><!--/name-->
-			d.lines[startIndex] = insertBetweenMatches(
-				d.lines[startIndex],
-				startCommentPattern(sectionName),
-				endCommentPattern(sectionName),
-				strings.TrimSpace(content),
-			)
-		} else {
-			// Multi-line format: clear and insert in one operation
-			// Remove old content and closing tag
-			d.removeLines(startIndex+1, endIndex+1)
-			// Add new content and closing tag
-			d.insertAfterIndex(
-				startIndex,
-				strings.TrimSpace(content),
-				fmt.Sprintf("<!--/%s-->", sectionName),
-			)
-		}
-	}
+func (d *Doc) updateDescription(a *action.Action) error {
+	return d.updateSections(d.sectionsNamed(descriptionSectionName), a, func(_ section, a *action.Action) (string, error) {
+		return a.Description, nil
+	})
 }
 
-func (d *Doc) updateDescription(description string) {
-	d.clearSection(descriptionSectionName)
-	d.insertSection(descriptionSectionName, description)
+func (d *Doc) updateSecrets(a *action.Action) error {
+	return d.updateSections(d.sectionsNamed(secretsSectionName), a, func(_ section, a *action.Action) (string, error) {
+		return table(a.GetSecretsMatrix()), nil
+	})
 }
 
-func (d *Doc) updateInputs(inputsMatrix [][]string) {
-	d.clearSection(inputsSectionName)
-	d.insertSection(inputsSectionName, table(inputsMatrix))
+func (d *Doc) updateRuns(a *action.Action) error {
+	return d.updateSections(d.sectionsNamed(runsSectionName), a, func(_ section, a *action.Action) (string, error) {
+		return table(a.GetRunsMatrix()), nil
+	})
 }
 
-func (d *Doc) updateOutputs(outputsMatrix [][]string) {
-	d.clearSection(outputsSectionName)
-	d.insertSection(outputsSectionName, table(outputsMatrix))
+// updateTOC regenerates the table of contents from the headings of the document.
+// It has to run after all other sections are updated so generated headings are included.
+func (d *Doc) updateTOC() error {
+	s, ok := d.firstSection(tableOfContentsSectionName)
+	if !ok {
+		return nil
+	}
+	minDepth, maxDepth := 2, 6
+	if depth, err := getAttribute(s.start.text, "depth"); err == nil {
+		minDepth, maxDepth, err = parseDepth(depth)
+		if err != nil {
+			return err
+		}
+	}
+	d.replaceSection(s, toc(d.headings(), 2, minDepth, maxDepth))
+	return nil
 }
 
-//func (d *Doc) updateTOC() {
-//	d.clearSection(tableOfContentsSectionName)
-//	d.insertSection(tableOfContentsSectionName, TableOfContents(d.lines))
-//}
-
-// hasPlaceholders checks if the document contains any placeholder comments
+// placeholders are the names of all placeholders
+var placeholders = []string{
+	nameSectionName,
+	descriptionSectionName,
+	inputsSectionName,
+	outputsSectionName,
+	usageSectionName,
+	usageExampleSectionName,
+	runsSectionName,
+	secretsSectionName,
+	tableOfContentsSectionName,
+	actionsIndexSectionName,
+	templateSectionName,
+	includeSectionName,
+	exampleSectionName,
+}
+
+// hasPlaceholders checks if the document contains any placeholder comments, start or end markers
 func (d *Doc) hasPlaceholders() bool {
-    placeholders := []string{
-		nameSectionName,
-		descriptionSectionName,
-		inputsSectionName,
-		outputsSectionName,
-		usageSectionName,
-	}
-	for _, placeholder := range placeholders {
-		if d.findIndex(startCommentPattern(placeholder)) != -1 {
+	for _, m := range d.markers() {
+		if slices.Contains(placeholders, m.name) {
 			return true
 		}
 	}
 	return false
 }
 
+// Update updates all placeholder sections of the document.
+// Sections document the given action, unless their action attribute references another action file.
+// The given action can be nil if the README only documents other actions.
 func (d *Doc) Update(a *action.Action) error {
 	// If file has no placeholders, skip all updates
 	if !d.hasPlaceholders() {
 		return nil
 	}
 	// File has placeholders, proceed with updates
+	if d.autoClose {
+		d.closeSections()
+	}
+	if err := d.checkMarkers(); err != nil {
+		return err
+	}
 	d.ensureGeneratedComment()
-	d.updateName(a.Name)
-	d.updateDescription(a.Description)
-	d.updateInputs(a.GetInputsMatrix())
-	d.updateOutputs(a.GetOutputsMatrix())
-	return d.UpdateUsage(a)
+	updates := []func(*action.Action) error{
+		d.updateName,
+		d.updateDescription,
+		d.updateInputs,
+		d.updateOutputs,
+		d.updateSecrets,
+		d.updateRuns,
+		d.updateTemplates,
+	}
+	for _, update := range updates {
+		if err := update(a); err != nil {
+			return err
+		}
+	}
+	if err := d.UpdateUsage(a); err != nil {
+		return err
+	}
+	if err := d.updateUsageExamples(a); err != nil {
+		return err
+	}
+	if err := d.updateActionsIndex(); err != nil {
+		return err
+	}
+	if err := d.updateIncludes(); err != nil {
+		return err
+	}
+	if err := d.updateExamples(); err != nil {
+		return err
+	}
+	return d.updateTOC()
 }
 
 func (d *Doc) Copy() Doc {
-	lines := make([]string, len(d.lines))
-	copy(lines, d.lines)
+	source := make([]byte, len(d.source))
+	copy(source, d.source)
 	return Doc{
-		name:  d.name,
-		lines: lines,
+		name:      d.name,
+		source:    source,
+		settings:  d.settings,
+		autoClose: d.autoClose,
 	}
 }
 
 func (d *Doc) ToString() string {
-	return strings.Join(d.lines, "\n")
+	return string(d.source)
 }
 
 func (d *Doc) WriteToFile() error {
@@ -197,11 +244,11 @@ func (d *Doc) WriteToFile() error {
 
 func (d *Doc) ensureGeneratedComment() {
 	// Check if the comment already exists at the top
-	if len(d.lines) > 0 && d.lines[0] == generatedComment {
+	if string(d.source[:lineEnd(d.source, 0)]) == generatedComment {
 		return
 	}
 	// Prepend the comment
-	d.lines = append([]string{generatedComment}, d.lines...)
+	d.splice(0, 0, generatedComment+"\n")
 }
 
 func readFile(name string) (string, error) {
@@ -212,247 +259,78 @@ func readFile(name string) (string, error) {
 	return string(file), nil
 }
 
-func (d *Doc) findIndex(pattern string) int {
-	r := regexp.MustCompile(pattern)
-	for i, line := range d.lines {
-		if r.MatchString(line) && !d.IsInsideCodeBlock(i) {
-			return i
-		}
-	}
-	return -1
-}
-
-func (d *Doc) findAllIndices(pattern string) []int {
-	r := regexp.MustCompile(pattern)
-	indices := []int{}
-	for i, line := range d.lines {
-		if r.MatchString(line) && !d.IsInsideCodeBlock(i) {
-			indices = append(indices, i)
-		}
-	}
-	return indices
-}
-
-func (d *Doc) insertAfterPrefix(prefix string, lines ...string) {
-	index := d.findIndex(prefix)
-	d.insertAfterIndex(index, lines...)
-}
-
-func (d *Doc) insertAfterIndex(index int, lines ...string) {
-	d.lines = append(d.lines[:index+1], append(lines, d.lines[index+1:]...)...)
-}
-
-func (d *Doc) removeLines(start int, end int) {
-	d.lines = append(d.lines[:start], d.lines[end:]...)
-}
-
-func startCommentPattern(name string) string {
-	return fmt.Sprintf("<!--\\s*%s(\\s+\\w+=\"\\S+\")*\\s*-->", name)
-}
-
-func endCommentPattern(name string) string {
-	return fmt.Sprintf("<!--\\s*\\/\\s*%s\\s*-->", name)
-}
-
-func insertBetweenMatches(str string, pattern1 string, pattern2 string, insertion string) string {
-	re1 := regexp.MustCompile(pattern1)
-	loc1 := re1.FindStringIndex(str)
-	if loc1 == nil {
-		return str
-	}
-	re2 := regexp.MustCompile(pattern2)
-	loc2 := re2.FindStringIndex(str)
-	if loc2 == nil {
-		return str
-	}
-	return str[:loc1[1]] + insertion + str[loc2[0]:]
-}
-
-func (d *Doc) insertSection(name string, content string) {
-	startIndex := d.findIndex(startCommentPattern(name))
-	endIndex := d.findIndex(endCommentPattern(name))
-
-	if startIndex == -1 {
-		return
-	}
-
-	if startIndex == endIndex {
-		d.lines[startIndex] = insertBetweenMatches(
-			d.lines[startIndex],
-			startCommentPattern(name),
-			endCommentPattern(name),
-			strings.TrimSpace(content),
-		)
-		return
-	}
-	d.insertAfterIndex(
-		startIndex,
-		strings.TrimSpace(content),
-		fmt.Sprintf("<!--/%s-->", name),
-	)
-}
-
-func (d *Doc) clearSection(name string) {
-	endIndex := d.findIndex(endCommentPattern(name))
-	if endIndex == -1 {
-		return
-	}
-	startIndex := d.findIndex(startCommentPattern(name))
-	d.removeLines(startIndex+1, endIndex+1)
-}
-
 func (d *Doc) GetName() string {
 	return d.name
 }
 
-func (d *Doc) Diff(doc *Doc) DiffResult {
-	dmp := diffmatchpatch.New()
-	diffs := dmp.DiffMain(d.ToString(), doc.ToString(), false)
-	
-	// Check if there are actual differences (any Insert or Delete operations)
-	hasDiff := false
-	for _, diff := range diffs {
-		if diff.Type != diffmatchpatch.DiffEqual {
-			hasDiff = true
-			break
-		}
-	}
-	
-	if !hasDiff {
-		return DiffResult{
-			PrettyDiff: "",
-			HasDiff:    false,
-		}
-	}
-	
-	// Create a unified diff format
-	var prettyDiff strings.Builder
-	for _, diff := range diffs {
-		switch diff.Type {
-		case diffmatchpatch.DiffInsert:
-			// Green for additions
-			lines := strings.Split(diff.Text, "\n")
-			for _, line := range lines {
-				if line != "" {
-					prettyDiff.WriteString(fmt.Sprintf("\x1b[32m+ %s\x1b[0m\n", line))
-				}
-			}
-		case diffmatchpatch.DiffDelete:
-			// Red for deletions
-			lines := strings.Split(diff.Text, "\n")
-			for _, line := range lines {
-				if line != "" {
-					prettyDiff.WriteString(fmt.Sprintf("\x1b[31m- %s\x1b[0m\n", line))
-				}
-			}
-		case diffmatchpatch.DiffEqual:
-			// Show context (3 lines before and after changes)
-			lines := strings.Split(diff.Text, "\n")
-			// For context, only show a few lines around changes
-			if len(lines) > 6 {
-				// Show first 3 lines
-				for i := 0; i < 3 && i < len(lines); i++ {
-					if lines[i] != "" {
-						prettyDiff.WriteString(fmt.Sprintf("  %s\n", lines[i]))
-					}
-				}
-				if len(lines) > 6 {
-					prettyDiff.WriteString("  ...\n")
-				}
-				// Show last 3 lines
-				for i := max(3, len(lines)-3); i < len(lines); i++ {
-					if lines[i] != "" {
-						prettyDiff.WriteString(fmt.Sprintf("  %s\n", lines[i]))
-					}
-				}
-			} else {
-				for _, line := range lines {
-					if line != "" {
-						prettyDiff.WriteString(fmt.Sprintf("  %s\n", line))
-					}
-				}
-			}
-		}
-	}
-	
-	return DiffResult{
-		PrettyDiff: prettyDiff.String(),
-		HasDiff:    hasDiff,
-	}
-}
-
-func max(a, b int) int {
-	if a > b {
-		return a
-	}
-	return b
-}
-
-type DiffResult struct {
-	PrettyDiff string
-	HasDiff    bool
-}
-
 func (d *Doc) UpdateUsage(a *action.Action) error {
 	// Find all usage sections
-	usageStartIndices := d.findAllIndices(startCommentPattern(usageSectionName))
-	usageEndIndices := d.findAllIndices(endCommentPattern(usageSectionName))
-	
-	if len(usageStartIndices) != len(usageEndIndices) {
-		// Check if there's a missing end comment for any usage section
-		if len(usageStartIndices) > 0 && len(usageEndIndices) == 0 {
+	sections := d.sectionsNamed(usageSectionName)
+
+	// Process each usage section independently
+	versionedActionRe := regexp.MustCompile(`uses:\s*(\S+)@\S+`)
+	edits := make([]edit, 0, len(sections))
+	for _, s := range sections {
+		if s.end == nil {
 			return fmt.Errorf("missing end comment for usage section. add <!--/usage--> to the end of the usage section")
 		}
-		return nil
-	}
-	
-	// Process each usage section independently (in reverse order to handle index shifts)
-	for i := len(usageStartIndices) - 1; i >= 0; i-- {
-		usageIndex := usageStartIndices[i]
-		usageEndIndex := usageEndIndices[i]
-		
-		if usageIndex == -1 || usageEndIndex == -1 {
-			continue
-		}
-		
 		// Get attributes for this specific usage section
-		version, err := getAttribute(d.lines[usageIndex], "version")
-		if err != nil {
-			return err
-		}
-		version, err = parseEnvVariable(version)
+		actionGlob, version, err := d.getUsageAttributes(s.start.text)
 		if err != nil {
 			return err
 		}
-		actionGlob, err := getAttribute(d.lines[usageIndex], "action")
-		if err != nil {
-			return err
-		}
-		
+
 		// Update only within this usage section
-		versionedActionRe := regexp.MustCompile(`uses:\s*(\S+)@\S+`)
-		if usageEndIndex > 0 && usageEndIndex > usageIndex {
-			for j := usageIndex; j < usageEndIndex; j += 1 {
-				submatch := versionedActionRe.FindStringSubmatch(d.lines[j])
-				if len(submatch) == 2 {
-					actionName := submatch[1]
-					globMatch, _ := doublestar.Match(actionGlob, actionName)
-					if globMatch {
-						pattern := strings.ReplaceAll(fmt.Sprintf("%s@\\S+", actionName), "/", "\\/")
-						re := regexp.MustCompile(pattern)
-						d.lines[j] = re.ReplaceAllString(d.lines[j], fmt.Sprintf("%s@%s", actionName, version))
-					}
+		lines := strings.Split(d.content(s), "\n")
+		for j, line := range lines {
+			submatch := versionedActionRe.FindStringSubmatch(line)
+			if len(submatch) == 2 {
+				actionName := submatch[1]
+				globMatch, _ := doublestar.Match(actionGlob, actionName)
+				if globMatch {
+					pattern := strings.ReplaceAll(fmt.Sprintf("%s@\\S+", actionName), "/", "\\/")
+					re := regexp.MustCompile(pattern)
+					lines[j] = re.ReplaceAllString(line, fmt.Sprintf("%s@%s", actionName, version))
 				}
 			}
 		}
+		edits = append(edits, edit{s.start.end, s.end.start, strings.Join(lines, "\n")})
 	}
+	d.apply(edits...)
 	return nil
 }
 
-func parseEnvVariable(variable string) (string, error) {
+// getUsageAttributes returns the uses reference and version attributes of a usage placeholder.
+// The version can reference an environment variable (e.g. version="env:VERSION").
+// If the action attribute is a path to a local action, the reference is taken from the uses attribute.
+func (d *Doc) getUsageAttributes(line string) (string, string, error) {
+	version, err := getAttribute(line, "version")
+	if err != nil {
+		return "", "", err
+	}
+	version, err = d.parseEnvVariable(version)
+	if err != nil {
+		return "", "", err
+	}
+	actionName, err := getAttribute(line, "action")
+	if err != nil {
+		return "", "", err
+	}
+	if isLocalAction(actionName) {
+		actionName, err = getAttribute(line, "uses")
+		if err != nil {
+			return "", "", fmt.Errorf("missing uses attribute for local action %q. add the reference used in workflows, e.g. uses=\"owner/repo/path\"", actionName)
+		}
+	}
+	return actionName, version, nil
+}
+
+// parseEnvVariable returns the value of the environment variable if the variable is env:NAME.
+// Variables that aren't set in the environment are looked up in the env of the settings.
+func (d *Doc) parseEnvVariable(variable string) (string, error) {
 	if strings.HasPrefix(variable, "env:") {
 		envVarName := strings.TrimPrefix(variable, "env:")
-		envVarValue := os.Getenv(envVarName)
+		envVarValue := d.settings.lookupEnv(envVarName)
 		if envVarValue == "" {
 			return "", fmt.Errorf("the environment variable %s is not set", envVarName)
 		}
@@ -463,10 +341,11 @@ func parseEnvVariable(variable string) (string, error) {
 }
 
 func getAttribute(line string, attribute string) (string, error) {
-	pattern := regexp.MustCompile(fmt.Sprintf("<!--.*%s=\"(\\S*)\".*-->", attribute))
-	matches := pattern.FindStringSubmatch(line)
-	if len(matches) >= 2 {
-		return matches[1], nil
+	comment := commentPattern.FindString(line)
+	for _, match := range attributePattern.FindAllStringSubmatch(comment, -1) {
+		if match[1] == attribute {
+			return match[2], nil
+		}
 	}
 	return "", fmt.Errorf("failed to get attribute %s", attribute)
 }
@@ -474,18 +353,3 @@ func getAttribute(line string, attribute string) (string, error) {
 func (d *Doc) Equals(doc Doc) bool {
 	return d.ToString() == doc.ToString()
 }
-
-// IsInsideCodeBlock checks if the line at the given index is inside a markdown code block
-func (d *Doc) IsInsideCodeBlock(lineIndex int) bool {
-	// Match any number of backticks (3 or more) at the start of a line, optionally followed by a language identifier
-	codeBlockRegex := regexp.MustCompile("^`{3,}")
-	inCodeBlock := false
-	
-	for i := 0; i <= lineIndex; i++ {
-		if i < len(d.lines) && codeBlockRegex.MatchString(d.lines[i]) {
-			inCodeBlock = !inCodeBlock
-		}
-	}
-	
-	return inCodeBlock
-}
@@ -0,0 +1,76 @@
+package markdown
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/reakaleek/gh-action-readme/internal/action"
+)
+
+// catalogReadme returns a README like the generated catalogs of repositories with many actions:
+// every entry has a heading with the name placeholder, a description, a code block and a usage section
+func catalogReadme(entries int) []byte {
+	var sb strings.Builder
+	sb.WriteString("# <!--name--><!--/name-->\n<!--description-->\n<!--/description-->\n")
+	sb.WriteString("## Inputs\n<!--inputs-->\n<!--/inputs-->\n## Outputs\n<!--outputs-->\n<!--/outputs-->\n")
+	for i := 0; i < entries; i++ {
+		fmt.Fprintf(&sb, "## Entry %d of <!--name--><!--/name-->\n\n", i)
+		sb.WriteString("Some text about this entry, with `inline code` and a [link](https://example.com).\n\n")
+		sb.WriteString("```yaml\n# <!--inputs--> in a code block is not a placeholder\nkey: value\n```\n\n")
+		fmt.Fprintf(&sb, "<!--usage action=\"owner/repo\" version=\"v2\"-->\n```yaml\n- uses: owner/repo@v1\n  with:\n    token: entry-%d\n```\n<!--/usage-->\n\n", i)
+	}
+	return []byte(sb.String())
+}
+
+func benchmarkAction() *action.Action {
+	return action.New(
+		"Catalog",
+		"Author",
+		"Catalog description.",
+		action.Inputs{"token": action.Input{Description: "The token.", Required: true}},
+		[]string{"token"},
+		action.Outputs{"result": action.Output{Description: "The result."}},
+		[]string{"result"},
+	)
+}
+
+// writeCatalogReadme writes a catalog README with the given number of entries to a temporary file
+func writeCatalogReadme(b *testing.B, entries int) (string, int) {
+	source := catalogReadme(entries)
+	path := filepath.Join(b.TempDir(), "README.md")
+	if err := os.WriteFile(path, source, 0644); err != nil {
+		b.Fatal(err)
+	}
+	return path, len(source)
+}
+
+// BenchmarkUpdate reads a catalog README and updates it. It only uses NewDoc and Update and the placeholders of the
+// first release (name, description, inputs, outputs and usage), so the file can be copied unchanged into an older
+// checkout to measure the baseline:
+//
+//	go test -run '^$' -bench Update -count 6 ./internal/markdown/ > new.txt
+//	git worktree add ../baseline <commit> && cp internal/markdown/doc_benchmark_test.go ../baseline/internal/markdown/
+//	(cd ../baseline && go test -run '^$' -bench Update -count 6 ./internal/markdown/) > old.txt
+//	benchstat old.txt new.txt
+func BenchmarkUpdate(b *testing.B) {
+	a := benchmarkAction()
+	// Each entry has 17 lines
+	for _, entries := range []int{100, 1000, 10000} {
+		path, size := writeCatalogReadme(b, entries)
+		b.Run(fmt.Sprintf("entries=%d", entries), func(b *testing.B) {
+			b.SetBytes(int64(size))
+			for i := 0; i < b.N; i++ {
+				doc, err := NewDoc(path)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if err := doc.Update(a); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
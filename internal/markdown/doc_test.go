@@ -3,7 +3,6 @@ package markdown
 import (
 	"github.com/reakaleek/gh-action-readme/internal/action"
 	"github.com/stretchr/testify/assert"
-	"regexp"
 	"strings"
 	"testing"
 )
@@ -11,14 +10,14 @@ import (
 func TestDoc(t *testing.T) {
 	// arrange
 	doc := Doc{
-		lines: []string{
+		source: fromLines(
 			"Hello,",
 			"!",
-		},
+		),
 	}
 
 	// act
-	doc.insertAfterPrefix("Hello", "World")
+	doc.splice(6, 6, "\nWorld")
 
 	// assert
 	assert.Equal(t, "Hello,\nWorld\n!", doc.ToString())
@@ -27,43 +26,44 @@ func TestDoc(t *testing.T) {
 func TestInsertSection(t *testing.T) {
 	// arrange
 	doc := Doc{
-		lines: []string{
-			"<!-- title -->",
+		source: fromLines(
+			"<!-- name -->",
 			"World",
-		},
+		),
 	}
 
 	// act
-	doc.insertSection("title", "# Hello")
+	doc.closeSections()
+	updateFirstSection(&doc, nameSectionName, "# Hello")
 	// assert
-	assert.Equal(t, "<!-- title -->\n# Hello\n<!--/title-->\nWorld", doc.ToString())
+	assert.Equal(t, "<!-- name -->\n# Hello\n<!--/name-->\nWorld", doc.ToString())
 }
 
-func TestRemoveSection(t *testing.T) {
+func TestReplaceSection(t *testing.T) {
 	// arrange
 	doc := Doc{
-		lines: []string{
+		source: fromLines(
 			"<!-- title -->",
 			"# Hello",
 			"<!-- /title -->",
 			"World",
-		},
+		),
 	}
 
 	// act
-	doc.clearSection("title")
+	updateFirstSection(&doc, "title", "# Hi")
 
 	// assert
-	assert.Equal(t, "<!-- title -->\nWorld", doc.ToString())
+	assert.Equal(t, "<!-- title -->\n# Hi\n<!-- /title -->\nWorld", doc.ToString())
 }
 
 func TestDiff(t *testing.T) {
 	// arrange
 	doc := Doc{
-		lines: []string{"Hello, World"},
+		source: fromLines("Hello, World"),
 	}
 	otherDoc := Doc{
-		lines: []string{"Hello, World!"},
+		source: fromLines("Hello, World!"),
 	}
 
 	// act
@@ -78,10 +78,10 @@ func TestDiff(t *testing.T) {
 func TestDiffTrue(t *testing.T) {
 	// arrange
 	doc := Doc{
-		lines: []string{"Hello, World"},
+		source: fromLines("Hello, World"),
 	}
 	otherDoc := Doc{
-		lines: []string{"Hello, World!"},
+		source: fromLines("Hello, World!"),
 	}
 
 	// act
@@ -94,10 +94,10 @@ func TestDiffTrue(t *testing.T) {
 func TestDiffFalse(t *testing.T) {
 	// arrange
 	doc := Doc{
-		lines: []string{"Hello, World!"},
+		source: fromLines("Hello, World!"),
 	}
 	otherDoc := Doc{
-		lines: []string{"Hello, World!"},
+		source: fromLines("Hello, World!"),
 	}
 
 	// act
@@ -110,13 +110,13 @@ func TestDiffFalse(t *testing.T) {
 func TestSingleLineSection(t *testing.T) {
 	// arrange
 	doc := Doc{
-		lines: []string{
+		source: fromLines(
 			"<!-- name --><!-- /name -->",
-		},
+		),
 	}
 
 	// act
-	doc.updateName("Foo")
+	assert.NoError(t, doc.updateName(&action.Action{Name: "Foo"}))
 
 	// assert
 	assert.Equal(t, "<!-- name -->Foo<!-- /name -->", doc.ToString())
@@ -135,43 +135,43 @@ func TestGetAttribute(t *testing.T) {
 	assert.Equal(t, "v2", version)
 }
 
-func TestStartCommentPattern(t *testing.T) {
+func TestParseMarker(t *testing.T) {
 	// arrange
-	pattern := startCommentPattern("usage")
-	re := regexp.MustCompile(pattern)
-	line := "<!--usage-->"
+	comment := "<!--usage-->"
 
 	// act
-	result := re.MatchString(line)
+	result, ok := parseMarker(comment)
 
 	// assert
-	assert.True(t, result)
+	assert.True(t, ok)
+	assert.Equal(t, "usage", result.name)
+	assert.False(t, result.closing)
 }
 
-func TestStartCommentPattern2(t *testing.T) {
+func TestParseMarker2(t *testing.T) {
 	// arrange
-	pattern := startCommentPattern("usage")
-	re := regexp.MustCompile(pattern)
-	line := "<!--usage action=\"action\" version=\"v1\"-->"
+	comment := "<!--usage action=\"action\" version=\"v1\"-->"
 
 	// act
-	result := re.MatchString(line)
+	result, ok := parseMarker(comment)
 
 	// assert
-	assert.True(t, result)
+	assert.True(t, ok)
+	assert.Equal(t, "usage", result.name)
+	assert.Equal(t, comment, result.text)
 }
 
 func TestUpdateUsage(t *testing.T) {
 	// arrange
 	t.Setenv("VERSION", "v2")
 	doc := Doc{
-		lines: []string{
+		source: fromLines(
 			"<!-- usage action=\"elastic/oblt-actions/test\" version=\"env:VERSION\" -->",
 			"```yaml",
 			"    uses: elastic/oblt-actions/test@v1",
 			"```",
 			"<!--/usage-->",
-		},
+		),
 	}
 
 	// act
@@ -185,17 +185,20 @@ func TestUpdateUsage(t *testing.T) {
 func TestUpdate(t *testing.T) {
 	// arrange
 	doc := Doc{
-		lines: []string{
+		source: fromLines(
 			"<!--name--><!--/name-->",
 			"<!--description-->",
+			"<!--/description-->",
 			"<!--inputs-->",
+			"<!--/inputs-->",
 			"<!--outputs-->",
+			"<!--/outputs-->",
 			"<!-- usage action=\"elastic/oblt-actions/test\" version=\"v1\" -->",
 			"```yaml",
 			"    uses: elastic/oblt-actions/test@main",
 			"```",
 			"<!--/usage-->",
-		},
+		),
 	}
 
 	a := action.New(
@@ -252,7 +255,7 @@ func TestUpdate(t *testing.T) {
 func TestCopy(t *testing.T) {
 	// arrange
 	doc := Doc{
-		lines: []string{"Hello, World"},
+		source: fromLines("Hello, World"),
 	}
 
 	// act
@@ -266,36 +269,38 @@ func TestCopy(t *testing.T) {
 func TestEnsureGeneratedCommentAddsWhenMissing(t *testing.T) {
 	// arrange
 	doc := Doc{
-		lines: []string{
+		source: fromLines(
 			"# <!--name-->Test<!--/name-->",
 			"<!--description-->",
-		},
+		),
 	}
 
 	// act
 	doc.ensureGeneratedComment()
 
 	// assert
-	assert.Equal(t, "<!-- Generated by https://github.com/reakaleek/gh-action-readme -->", doc.lines[0])
-	assert.Equal(t, 3, len(doc.lines))
+	lines := strings.Split(doc.ToString(), "\n")
+	assert.Equal(t, "<!-- Generated by https://github.com/reakaleek/gh-action-readme -->", lines[0])
+	assert.Equal(t, 3, len(lines))
 }
 
 func TestEnsureGeneratedCommentDoesNotDuplicateWhenExists(t *testing.T) {
 	// arrange
 	doc := Doc{
-		lines: []string{
+		source: fromLines(
 			"<!-- Generated by https://github.com/reakaleek/gh-action-readme -->",
 			"# <!--name-->Test<!--/name-->",
 			"<!--description-->",
-		},
+		),
 	}
 
 	// act
 	doc.ensureGeneratedComment()
 
 	// assert
-	assert.Equal(t, "<!-- Generated by https://github.com/reakaleek/gh-action-readme -->", doc.lines[0])
-	assert.Equal(t, 3, len(doc.lines))
+	lines := strings.Split(doc.ToString(), "\n")
+	assert.Equal(t, "<!-- Generated by https://github.com/reakaleek/gh-action-readme -->", lines[0])
+	assert.Equal(t, 3, len(lines))
 }
 
 func TestNewEmptyDoc(t *testing.T) {
@@ -304,7 +309,7 @@ func TestNewEmptyDoc(t *testing.T) {
 
 	// assert
 	assert.Equal(t, "test.md", doc.name)
-	assert.Equal(t, 0, len(doc.lines))
+	assert.Equal(t, 0, len(doc.source))
 	assert.Equal(t, "", doc.ToString())
 }
 
@@ -315,7 +320,7 @@ func TestNewDocOrCreate_FileExists(t *testing.T) {
 	// assert
 	assert.NoError(t, err)
 	assert.NotNil(t, doc)
-	assert.Greater(t, len(doc.lines), 0)
+	assert.Greater(t, len(doc.source), 0)
 }
 
 func TestNewDocOrCreate_FileDoesNotExist(t *testing.T) {
@@ -345,7 +350,7 @@ func TestDiffEmptyWithContent(t *testing.T) {
 	// arrange
 	emptyDoc := NewEmptyDoc("test.md")
 	contentDoc := Doc{
-		lines: []string{"Some content"},
+		source: fromLines("Some content"),
 	}
 
 	// act
@@ -360,7 +365,7 @@ func TestUpdatePreservesEmptyLines(t *testing.T) {
 	// Test that empty lines between sections are preserved
 	// arrange
 	doc := Doc{
-		lines: []string{
+		source: fromLines(
 			"# <!--name--><!--/name-->",
 			"<!--description-->",
 			"<!--/description-->",
@@ -372,7 +377,7 @@ func TestUpdatePreservesEmptyLines(t *testing.T) {
 			"## Outputs",
 			"<!--outputs-->",
 			"<!--/outputs-->",
-		},
+		),
 	}
 
 	a := action.New(
@@ -415,16 +420,16 @@ func TestUpdatePreservesEmptyLines(t *testing.T) {
 func TestMultipleNamePlaceholders_TwoOccurrences(t *testing.T) {
 	// arrange
 	doc := Doc{
-		lines: []string{
+		source: fromLines(
 			"# <!--name--><!--/name-->",
 			"",
 			"## What is <!--name--><!--/name-->?",
 			"This is a test.",
-		},
+		),
 	}
 
 	// act
-	doc.updateName("My Action")
+	assert.NoError(t, doc.updateName(&action.Action{Name: "My Action"}))
 
 	// assert
 	expected := strings.Join([]string{
@@ -439,18 +444,18 @@ func TestMultipleNamePlaceholders_TwoOccurrences(t *testing.T) {
 func TestMultipleNamePlaceholders_ThreeOccurrences(t *testing.T) {
 	// arrange
 	doc := Doc{
-		lines: []string{
+		source: fromLines(
 			"# <!--name--><!--/name-->",
 			"",
 			"The <!--name--><!--/name--> action helps you...",
 			"",
 			"## Using <!--name--><!--/name-->",
 			"Some content here.",
-		},
+		),
 	}
 
 	// act
-	doc.updateName("Super Action")
+	assert.NoError(t, doc.updateName(&action.Action{Name: "Super Action"}))
 
 	// assert
 	expected := strings.Join([]string{
@@ -467,14 +472,14 @@ func TestMultipleNamePlaceholders_ThreeOccurrences(t *testing.T) {
 func TestMultipleNamePlaceholders_SingleLine(t *testing.T) {
 	// arrange - all on single lines
 	doc := Doc{
-		lines: []string{
+		source: fromLines(
 			"# <!--name--><!--/name-->",
 			"Welcome to <!--name--><!--/name-->",
-		},
+		),
 	}
 
 	// act
-	doc.updateName("Test Tool")
+	assert.NoError(t, doc.updateName(&action.Action{Name: "Test Tool"}))
 
 	// assert
 	expected := strings.Join([]string{
@@ -487,16 +492,16 @@ func TestMultipleNamePlaceholders_SingleLine(t *testing.T) {
 func TestMultipleNamePlaceholders_MixedFormats(t *testing.T) {
 	// arrange - mix of single-line and multi-line
 	doc := Doc{
-		lines: []string{
+		source: fromLines(
 			"# <!--name-->",
 			"<!--/name-->",
 			"",
 			"Welcome to <!--name--><!--/name-->",
-		},
+		),
 	}
 
 	// act
-	doc.updateName("Mixed Action")
+	assert.NoError(t, doc.updateName(&action.Action{Name: "Mixed Action"}))
 
 	// assert
 	expected := strings.Join([]string{
@@ -512,7 +517,7 @@ func TestMultipleNamePlaceholders_MixedFormats(t *testing.T) {
 func TestMultipleNamePlaceholders_PreservesContent(t *testing.T) {
 	// arrange
 	doc := Doc{
-		lines: []string{
+		source: fromLines(
 			"# <!--name--><!--/name-->",
 			"",
 			"This is custom content that should not change.",
@@ -520,11 +525,11 @@ func TestMultipleNamePlaceholders_PreservesContent(t *testing.T) {
 			"## About <!--name--><!--/name-->",
 			"",
 			"More custom content here.",
-		},
+		),
 	}
 
 	// act
-	doc.updateName("Preserve Test")
+	assert.NoError(t, doc.updateName(&action.Action{Name: "Preserve Test"}))
 
 	// assert
 	str := doc.ToString()
@@ -539,7 +544,7 @@ func TestMultipleNamePlaceholders_PreservesContent(t *testing.T) {
 func TestMultipleUsagePlaceholders_DifferentActions(t *testing.T) {
 	// arrange
 	doc := Doc{
-		lines: []string{
+		source: fromLines(
 			"## Usage with Action A",
 			"<!--usage action=\"owner/action-a\" version=\"v1\"-->",
 			"```yaml",
@@ -553,7 +558,7 @@ func TestMultipleUsagePlaceholders_DifferentActions(t *testing.T) {
 			"uses: owner/action-b@main",
 			"```",
 			"<!--/usage-->",
-		},
+		),
 	}
 
 	// act
@@ -568,7 +573,7 @@ func TestMultipleUsagePlaceholders_DifferentActions(t *testing.T) {
 func TestMultipleUsagePlaceholders_SameAction(t *testing.T) {
 	// arrange
 	doc := Doc{
-		lines: []string{
+		source: fromLines(
 			"## Basic Usage",
 			"<!--usage action=\"myorg/myaction\" version=\"v1.0.0\"-->",
 			"```yaml",
@@ -582,7 +587,7 @@ func TestMultipleUsagePlaceholders_SameAction(t *testing.T) {
 			"uses: myorg/myaction@main",
 			"```",
 			"<!--/usage-->",
-		},
+		),
 	}
 
 	// act
@@ -624,7 +629,7 @@ func TestMultipleUsagePlaceholders_SameAction(t *testing.T) {
 func TestMultipleUsagePlaceholders_IndependentUpdate(t *testing.T) {
 	// arrange - each section should only update its matching action
 	doc := Doc{
-		lines: []string{
+		source: fromLines(
 			"<!--usage action=\"elastic/*\" version=\"v1\"-->",
 			"```yaml",
 			"uses: elastic/action-test@main",
@@ -638,7 +643,7 @@ func TestMultipleUsagePlaceholders_IndependentUpdate(t *testing.T) {
 			"uses: other/action@main",
 			"```",
 			"<!--/usage-->",
-		},
+		),
 	}
 
 	// act
@@ -673,7 +678,7 @@ func TestMultipleUsagePlaceholders_EnvironmentVariable(t *testing.T) {
 	t.Setenv("VERSION_B", "v2.3.0")
 	
 	doc := Doc{
-		lines: []string{
+		source: fromLines(
 			"<!--usage action=\"owner/action-a\" version=\"env:VERSION_A\"-->",
 			"```yaml",
 			"uses: owner/action-a@main",
@@ -685,7 +690,7 @@ func TestMultipleUsagePlaceholders_EnvironmentVariable(t *testing.T) {
 			"uses: owner/action-b@main",
 			"```",
 			"<!--/usage-->",
-		},
+		),
 	}
 
 	// act
@@ -700,13 +705,13 @@ func TestMultipleUsagePlaceholders_EnvironmentVariable(t *testing.T) {
 func TestMultipleUsagePlaceholders_NoMatch(t *testing.T) {
 	// arrange - usage section where action glob doesn't match any uses: lines
 	doc := Doc{
-		lines: []string{
+		source: fromLines(
 			"<!--usage action=\"nomatch/*\" version=\"v1\"-->",
 			"```yaml",
 			"uses: other/action@main",
 			"```",
 			"<!--/usage-->",
-		},
+		),
 	}
 
 	// act
@@ -721,7 +726,7 @@ func TestMultipleUsagePlaceholders_NoMatch(t *testing.T) {
 func TestMultipleUsagePlaceholders_ComplexGlob(t *testing.T) {
 	// arrange
 	doc := Doc{
-		lines: []string{
+		source: fromLines(
 			"<!--usage action=\"elastic/oblt-actions/*\" version=\"v1\"-->",
 			"```yaml",
 			"uses: elastic/oblt-actions/test@main",
@@ -735,7 +740,7 @@ func TestMultipleUsagePlaceholders_ComplexGlob(t *testing.T) {
 			"uses: actions/checkout@v3",
 			"```",
 			"<!--/usage-->",
-		},
+		),
 	}
 
 	// act
@@ -755,7 +760,7 @@ func TestMultipleUsagePlaceholders_ComplexGlob(t *testing.T) {
 func TestMultiplePlaceholders_NameAndUsage(t *testing.T) {
 	// arrange - multiple names and multiple usages together
 	doc := Doc{
-		lines: []string{
+		source: fromLines(
 			"# <!--name--><!--/name-->",
 			"",
 			"## About <!--name--><!--/name-->",
@@ -772,7 +777,7 @@ func TestMultiplePlaceholders_NameAndUsage(t *testing.T) {
 			"uses: owner/action@main",
 			"```",
 			"<!--/usage-->",
-		},
+		),
 	}
 
 	a := action.New(
@@ -812,7 +817,7 @@ func TestMultiplePlaceholders_NameAndUsage(t *testing.T) {
 func TestMultiplePlaceholders_WithSinglePlaceholders(t *testing.T) {
 	// arrange - multiple names/usages with single inputs/outputs
 	doc := Doc{
-		lines: []string{
+		source: fromLines(
 			"# <!--name--><!--/name-->",
 			"<!--description-->",
 			"<!--/description-->",
@@ -828,7 +833,7 @@ func TestMultiplePlaceholders_WithSinglePlaceholders(t *testing.T) {
 			"uses: test/action@main",
 			"```",
 			"<!--/usage-->",
-		},
+		),
 	}
 
 	a := action.New(
@@ -870,11 +875,11 @@ func TestMultiplePlaceholders_WithSinglePlaceholders(t *testing.T) {
 func TestMultiplePlaceholders_BackwardCompatibility(t *testing.T) {
 	// arrange - single placeholder should still work
 	doc := Doc{
-		lines: []string{
+		source: fromLines(
 			"# <!--name--><!--/name-->",
 			"<!--description-->",
 			"<!--/description-->",
-		},
+		),
 	}
 
 	a := action.New(
@@ -907,14 +912,14 @@ func TestMultiplePlaceholders_BackwardCompatibility(t *testing.T) {
 func TestMultiplePlaceholders_EmptyContent(t *testing.T) {
 	// arrange - empty name between tags
 	doc := Doc{
-		lines: []string{
+		source: fromLines(
 			"# <!--name--><!--/name-->",
 			"About <!--name--><!--/name-->",
-		},
+		),
 	}
 
 	// act
-	doc.updateName("")
+	assert.NoError(t, doc.updateName(&action.Action{Name: ""}))
 
 	// assert
 	expected := strings.Join([]string{
@@ -927,14 +932,14 @@ func TestMultiplePlaceholders_EmptyContent(t *testing.T) {
 func TestMultiplePlaceholders_WhitespaceVariations(t *testing.T) {
 	// arrange - various whitespace in tags
 	doc := Doc{
-		lines: []string{
+		source: fromLines(
 			"# <!-- name --><!-- /name -->",
 			"About <!--  name  --><!--  /name  -->",
-		},
+		),
 	}
 
 	// act
-	doc.updateName("Whitespace Test")
+	assert.NoError(t, doc.updateName(&action.Action{Name: "Whitespace Test"}))
 
 	// assert
 	str := doc.ToString()
@@ -946,12 +951,12 @@ func TestMultiplePlaceholders_WhitespaceVariations(t *testing.T) {
 func TestMultiplePlaceholders_MissingClosingTag(t *testing.T) {
 	// arrange - unpaired usage tags
 	doc := Doc{
-		lines: []string{
+		source: fromLines(
 			"<!--usage action=\"test/action\" version=\"v1\"-->",
 			"```yaml",
 			"uses: test/action@main",
 			"```",
-		},
+		),
 	}
 
 	// act
@@ -961,3 +966,153 @@ func TestMultiplePlaceholders_MissingClosingTag(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "missing end comment for usage section")
 }
+
+func TestMultiplePlaceholders_MissingSecondClosingTag(t *testing.T) {
+	// arrange - the second usage section is not closed
+	doc := Doc{
+		source: fromLines(
+			"<!--usage action=\"test/action\" version=\"v1\"-->",
+			"```yaml",
+			"uses: test/action@main",
+			"```",
+			"<!--/usage-->",
+			"<!--usage action=\"test/action\" version=\"v1\"-->",
+			"```yaml",
+			"uses: test/action@main",
+			"```",
+		),
+	}
+
+	// act
+	err := doc.UpdateUsage(nil)
+
+	// assert
+	assert.EqualError(t, err, "missing end comment for usage section. add <!--/usage--> to the end of the usage section")
+}
+
+func TestUpdateColumns(t *testing.T) {
+	// arrange
+	doc := Doc{
+		source: fromLines(
+			"<!--inputs columns=\"name,default\" name-label=\"Input\"-->",
+			"<!--/inputs-->",
+			"<!--outputs columns=\"name,value\"-->",
+			"<!--/outputs-->",
+		),
+	}
+	a := action.New(
+		"Test",
+		"Author",
+		"Test description.",
+		action.Inputs{
+			"input1": action.Input{
+				Description: "input1 description.",
+				Default:     "foo",
+			},
+		},
+		[]string{"input1"},
+		action.Outputs{
+			"output1": action.Output{
+				Description: "output1 description.",
+				Value:       "bar",
+			},
+		},
+		[]string{"output1"},
+	)
+
+	// act
+	err := doc.Update(a)
+
+	// assert
+	assert.NoError(t, err)
+	expected := strings.Join([]string{
+		"<!-- Generated by https://github.com/reakaleek/gh-action-readme -->",
+		"<!--inputs columns=\"name,default\" name-label=\"Input\"-->",
+		"| Input    | Default |",
+		"|----------|---------|",
+		"| `input1` | `foo`   |",
+		"<!--/inputs-->",
+		"<!--outputs columns=\"name,value\"-->",
+		"| Name      | Value |",
+		"|-----------|-------|",
+		"| `output1` | `bar` |",
+		"<!--/outputs-->",
+	}, "\n")
+	assert.Equal(t, expected, doc.ToString())
+}
+
+func TestUpdateUnknownColumn(t *testing.T) {
+	// arrange
+	doc := Doc{
+		source: fromLines(
+			"<!--inputs columns=\"name,foo\"-->",
+			"<!--/inputs-->",
+		),
+	}
+	a := action.New("Test", "Author", "Test description.", action.Inputs{}, []string{}, action.Outputs{}, []string{})
+
+	// act
+	err := doc.Update(a)
+
+	// assert
+	assert.ErrorContains(t, err, `unknown inputs column "foo"`)
+}
+
+func TestUpdateTOC(t *testing.T) {
+	// arrange
+	doc := Doc{
+		source: fromLines(
+			"# <!--name--><!--/name-->",
+			"<!--toc depth=\"2-3\"-->",
+			"<!--/toc-->",
+			"## Usage",
+			"```yaml",
+			"# not a heading",
+			"```",
+			"### With inputs",
+			"#### Too deep",
+			"## Usage",
+		),
+	}
+	a := action.New("My Action", "Author", "", action.Inputs{}, nil, action.Outputs{}, nil)
+
+	// act
+	err := doc.Update(a)
+
+	// assert
+	assert.NoError(t, err)
+	expected := strings.Join([]string{
+		"<!-- Generated by https://github.com/reakaleek/gh-action-readme -->",
+		"# <!--name-->My Action<!--/name-->",
+		"<!--toc depth=\"2-3\"-->",
+		"- [Usage](#usage)",
+		"  - [With inputs](#with-inputs)",
+		"- [Usage](#usage-1)",
+		"<!--/toc-->",
+		"## Usage",
+		"```yaml",
+		"# not a heading",
+		"```",
+		"### With inputs",
+		"#### Too deep",
+		"## Usage",
+	}, "\n")
+	assert.Equal(t, expected, doc.ToString())
+}
+
+func TestUpdateTOCInvalidDepth(t *testing.T) {
+	// arrange
+	doc := Doc{source: fromLines("<!--toc depth=\"deep\"-->", "<!--/toc-->")}
+	a := action.New("My Action", "Author", "", action.Inputs{}, nil, action.Outputs{}, nil)
+
+	// act
+	err := doc.Update(a)
+
+	// assert
+	assert.ErrorContains(t, err, "invalid toc depth")
+}
+
+// fromLines returns the source of a document with the given lines
+func fromLines(lines ...string) []byte {
+	return []byte(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,254 @@
+package markdown
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/reakaleek/gh-action-readme/internal/action"
+)
+
+// maxInlineDrift is the length up to which a changed single line is quoted in the drift of a section
+const maxInlineDrift = 60
+
+// drift describes how the content of a stale section differs from the generated section.
+// Tables of inputs and outputs are compared row by row with the matrix of the action.
+// Example: "2 rows changed (added `token`, default of `retries` changed)"
+func (d *Doc) drift(current section, expected *Doc, s section, a *action.Action) string {
+	if current.end == nil || s.end == nil {
+		return "not closed"
+	}
+	content := d.content(current)
+	if s.start.name == inputsSectionName || s.start.name == outputsSectionName {
+		if detail, ok := expected.tableDrift(content, s, a); ok {
+			return detail
+		}
+	}
+	return contentDrift(content, expected.content(s))
+}
+
+// contentDrift describes a changed section by its lines, or quotes it if it is a short single line
+// Example: `changed from "Old" to "New"` or "content changed (2 lines added, 1 line removed)"
+func contentDrift(current string, expected string) string {
+	current, expected = strings.TrimSpace(current), strings.TrimSpace(expected)
+	if current == expected {
+		// Only leading or trailing whitespace differs
+		return "formatting changed"
+	}
+	if isInline(current) && isInline(expected) {
+		return fmt.Sprintf("changed from %q to %q", current, expected)
+	}
+	added, removed := 0, 0
+	for _, line := range diffLines(current+"\n", expected+"\n") {
+		switch line.op {
+		case '+':
+			added++
+		case '-':
+			removed++
+		}
+	}
+	var counts []string
+	if added > 0 {
+		counts = append(counts, fmt.Sprintf("%s added", plural(added, "line")))
+	}
+	if removed > 0 {
+		counts = append(counts, fmt.Sprintf("%s removed", plural(removed, "line")))
+	}
+	return fmt.Sprintf("content changed (%s)", strings.Join(counts, ", "))
+}
+
+func isInline(text string) bool {
+	return !strings.Contains(text, "\n") && utf8.RuneCountInString(text) <= maxInlineDrift
+}
+
+// tableDrift compares the rows of the tables in the content of a stale inputs or outputs section with the matrix
+// of the section. It returns false if the section isn't rendered as table or the rows are the same.
+func (d *Doc) tableDrift(content string, s section, a *action.Action) (string, bool) {
+	sectionAction, err := d.actionFor(s, a)
+	if err != nil {
+		return "", false
+	}
+	matrix, options, err := sectionMatrix(s, sectionAction)
+	if err != nil || options.style != styleTable {
+		return "", false
+	}
+	expected := make([][]string, len(matrix))
+	for i, row := range matrix {
+		expected[i] = make([]string, len(row))
+		for j, cell := range row {
+			// Cells are compared as they are rendered in the table
+			expected[i][j] = TableCell(cell)
+		}
+	}
+	return compareRows(tableRows(content), expected, options.columns)
+}
+
+// sectionMatrix returns the matrix of all inputs or outputs of an inputs or outputs section.
+// Grouped tables are flattened into one matrix in the order of the groups.
+func sectionMatrix(s section, a *action.Action) ([][]string, tableOptions, error) {
+	line := s.start.text
+	defaultColumns := action.DefaultOutputColumns
+	if s.start.name == inputsSectionName {
+		defaultColumns = a.DefaultInputColumns()
+	}
+	options, err := getTableOptions(line, defaultColumns)
+	if err != nil {
+		return nil, options, err
+	}
+	var keys []string
+	if s.start.name == inputsSectionName {
+		keys, err = a.SelectInputs(options.selection)
+	} else {
+		keys, err = a.SelectOutputs(options.selection)
+	}
+	if err != nil {
+		return nil, options, err
+	}
+	if options.groupBy != "" {
+		var grouped []string
+		for _, g := range groupKeysByPrefix(keys) {
+			grouped = append(grouped, g.keys...)
+		}
+		keys = grouped
+	}
+	var matrix [][]string
+	if s.start.name == inputsSectionName {
+		matrix, err = a.GetInputsMatrixFor(keys, options.columns, options.labels)
+	} else {
+		matrix, err = a.GetOutputsMatrixFor(keys, options.columns, options.labels)
+	}
+	return matrix, options, err
+}
+
+// tableRows returns the header of the first table in the content followed by the rows of all tables.
+// The header and delimiter rows of the following tables, e.g. of grouped inputs, are skipped.
+// Returns nil if the content has no table.
+func tableRows(content string) [][]string {
+	var rows [][]string
+	rowOfTable := 0
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(strings.TrimLeft(line, " \t>"))
+		if !strings.HasPrefix(line, "|") {
+			rowOfTable = 0
+			continue
+		}
+		rowOfTable++
+		if rowOfTable == 2 || (rowOfTable == 1 && len(rows) > 0) {
+			continue
+		}
+		rows = append(rows, splitRow(line))
+	}
+	return rows
+}
+
+// splitRow splits a table row into its trimmed cells at the pipes that aren't escaped
+// Example: "| `a\|b` | text |" -> ["`a\|b`", "text"]
+func splitRow(line string) []string {
+	line = strings.TrimPrefix(line, "|")
+	var cells []string
+	var cell strings.Builder
+	escaped := false
+	for _, r := range line {
+		if r == '|' && !escaped {
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+			continue
+		}
+		escaped = r == '\\' && !escaped
+		cell.WriteRune(r)
+	}
+	if rest := strings.TrimSpace(cell.String()); rest != "" {
+		cells = append(cells, rest)
+	}
+	return cells
+}
+
+// compareRows compares the rows of a table with the rows of the matrix by the name column.
+// The first row of both are the headers. It returns false if there is no difference in the rows.
+func compareRows(current [][]string, expected [][]string, columns []string) (string, bool) {
+	var changes []string
+	if len(current) > 0 && !slices.Equal(current[0], expected[0]) {
+		changes = append(changes, "header changed")
+	}
+	var currentRows [][]string
+	if len(current) > 0 {
+		currentRows = current[1:]
+	}
+	expectedRows := expected[1:]
+	nameColumn := slices.Index(columns, action.ColumnName)
+	changed := 0
+	if nameColumn == -1 {
+		// Without names, the rows can only be compared by their position
+		for i := 0; i < max(len(currentRows), len(expectedRows)); i++ {
+			if i >= len(currentRows) || i >= len(expectedRows) || !slices.Equal(currentRows[i], expectedRows[i]) {
+				changed++
+			}
+		}
+	} else {
+		byName := make(map[string][]string)
+		var currentNames []string
+		for _, row := range currentRows {
+			if nameColumn < len(row) {
+				byName[row[nameColumn]] = row
+				currentNames = append(currentNames, row[nameColumn])
+			}
+		}
+		var expectedNames []string
+		for _, row := range expectedRows {
+			name := row[nameColumn]
+			expectedNames = append(expectedNames, name)
+			currentRow, ok := byName[name]
+			if !ok {
+				changes = append(changes, fmt.Sprintf("added %s", name))
+				changed++
+				continue
+			}
+			if changedColumns := changedColumns(currentRow, row, columns); len(changedColumns) > 0 {
+				changes = append(changes, fmt.Sprintf("%s of %s changed", strings.Join(changedColumns, " and "), name))
+				changed++
+			}
+		}
+		for _, name := range currentNames {
+			if !slices.Contains(expectedNames, name) {
+				changes = append(changes, fmt.Sprintf("removed %s", name))
+				changed++
+			}
+		}
+		if changed == 0 && !slices.Equal(currentNames, expectedNames) {
+			changes = append(changes, "rows reordered")
+		}
+	}
+	switch {
+	case changed > 0 && len(changes) > 0:
+		return fmt.Sprintf("%s changed (%s)", plural(changed, "row"), strings.Join(changes, ", ")), true
+	case changed > 0:
+		return fmt.Sprintf("%s changed", plural(changed, "row")), true
+	case len(changes) > 0:
+		return strings.Join(changes, ", "), true
+	default:
+		return "", false
+	}
+}
+
+// changedColumns returns the columns whose cells differ between two rows of the same name
+func changedColumns(current []string, expected []string, columns []string) []string {
+	if len(current) != len(expected) {
+		return []string{"columns"}
+	}
+	var changed []string
+	for i := range expected {
+		if current[i] != expected[i] {
+			changed = append(changed, columns[i])
+		}
+	}
+	return changed
+}
+
+// plural returns the count with the noun, with an s for any count but one
+func plural(count int, noun string) string {
+	if count == 1 {
+		return fmt.Sprintf("%d %s", count, noun)
+	}
+	return fmt.Sprintf("%d %ss", count, noun)
+}
@@ -0,0 +1,142 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/reakaleek/gh-action-readme/internal/action"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var driftAction = testAction([]string{"token", "retries", "debug"}, action.Inputs{
+	"token":   {Description: "The GitHub token", Required: true},
+	"retries": {Description: "Number of retries", Default: "5"},
+	"debug":   {Description: "Enable debug logs", Default: "false"},
+})
+
+func TestStaleSectionsTableDrift(t *testing.T) {
+	tests := []struct {
+		name   string
+		marker string
+		table  []string
+		detail string
+	}{
+		{
+			name:   "added row and changed default",
+			marker: "<!--inputs-->",
+			table: []string{
+				"| Name      | Description       | Required | Default |",
+				"|-----------|-------------------|----------|---------|",
+				"| `retries` | Number of retries | `false`  | `3`     |",
+				"| `debug`   | Enable debug logs | `false`  | `false` |",
+			},
+			detail: "2 rows changed (added `token`, default of `retries` changed)",
+		},
+		{
+			name:   "removed row and several columns",
+			marker: "<!--inputs-->",
+			table: []string{
+				"| Name      | Description       | Required | Default |",
+				"|-----------|-------------------|----------|---------|",
+				"| `token`   | The GitHub token  | `true`   | ` `     |",
+				"| `retries` | Retries           | `true`   | `5`     |",
+				"| `debug`   | Enable debug logs | `false`  | `false` |",
+				"| `verbose` | Verbose logs      | `false`  | `false` |",
+			},
+			detail: "2 rows changed (description and required of `retries` changed, removed `verbose`)",
+		},
+		{
+			name:   "reordered rows",
+			marker: "<!--inputs-->",
+			table: []string{
+				"| Name | Description | Required | Default |",
+				"|------|-------------|----------|---------|",
+				"| `debug` | Enable debug logs | `false` | `false` |",
+				"| `token` | The GitHub token | `true` | ` ` |",
+				"| `retries` | Number of retries | `false` | `5` |",
+			},
+			detail: "rows reordered",
+		},
+		{
+			name:   "changed header",
+			marker: "<!--inputs default-label=\"Default value\"-->",
+			table: []string{
+				"| Name | Description | Required | Default |",
+				"|------|-------------|----------|---------|",
+				"| `token` | The GitHub token | `true` | ` ` |",
+				"| `retries` | Number of retries | `false` | `5` |",
+				"| `debug` | Enable debug logs | `false` | `false` |",
+			},
+			detail: "header changed",
+		},
+		{
+			name:   "only padding",
+			marker: "<!--inputs-->",
+			table: []string{
+				"| Name | Description | Required | Default |",
+				"|------|-------------|----------|---------|",
+				"| `token` | The GitHub token | `true` | ` ` |",
+				"| `retries` | Number of retries | `false` | `5` |",
+				"| `debug` | Enable debug logs | `false` | `false` |",
+			},
+			detail: "content changed (5 lines added, 5 lines removed)",
+		},
+		{
+			name:   "without name column",
+			marker: "<!--inputs columns=\"description,default\"-->",
+			table: []string{
+				"| Description | Default |",
+				"|-------------|---------|",
+				"| The GitHub token | ` ` |",
+				"| Number of retries | `3` |",
+				"| Enable debug logs | `false` |",
+			},
+			detail: "1 row changed",
+		},
+		{
+			name:   "list style",
+			marker: "<!--inputs style=\"list\"-->",
+			table:  []string{"- `token`"},
+			detail: "content changed (9 lines added, 1 line removed)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// arrange
+			a := driftAction
+			lines := append(append([]string{tt.marker}, tt.table...), "<!--/inputs-->")
+			current := &Doc{name: "README.md", source: fromLines(lines...)}
+			expected := &Doc{name: "README.md", source: fromLines(lines...)}
+			require.NoError(t, expected.updateInputs(a))
+
+			// act
+			stale := current.StaleSections(expected, a)
+
+			// assert
+			require.Len(t, stale, 1)
+			assert.Equal(t, tt.detail, stale[0].Detail)
+		})
+	}
+}
+
+func TestContentDrift(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  string
+		expected string
+		detail   string
+	}{
+		{name: "single line", current: "Old", expected: "New", detail: `changed from "Old" to "New"`},
+		{name: "whitespace", current: "\nText\n", expected: "Text", detail: "formatting changed"},
+		{name: "lines", current: "a\nb\nc", expected: "a\nB\nc\nd", detail: "content changed (2 lines added, 1 line removed)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.detail, contentDrift(tt.current, tt.expected))
+		})
+	}
+}
+
+func TestSplitRow(t *testing.T) {
+	assert.Equal(t, []string{"`a\\|b`", "text", ""}, splitRow("| `a\\|b` | text |  |"))
+}
@@ -0,0 +1,96 @@
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/reakaleek/gh-action-readme/internal/action"
+	"gopkg.in/yaml.v3"
+)
+
+// updateExamples replaces every example section with a job or step extracted from a workflow
+// Example: <!--example workflow=".github/workflows/test.yml" job="e2e" step="run-action" action="org/repo" version="v1"-->
+func (d *Doc) updateExamples() error {
+	sections := d.sectionsNamed(exampleSectionName)
+	edits := make([]edit, 0, len(sections))
+	for _, s := range sections {
+		if !strings.Contains(d.content(s), "\n") {
+			return fmt.Errorf("example section must start and end on separate lines")
+		}
+		content, err := d.example(s.start.text)
+		if err != nil {
+			return err
+		}
+		edits = append(edits, d.sectionEdit(s, content))
+	}
+	d.apply(edits...)
+	return nil
+}
+
+func (d *Doc) example(line string) (string, error) {
+	workflow, err := getAttribute(line, "workflow")
+	if err != nil {
+		return "", fmt.Errorf("missing workflow attribute in example placeholder. add the path of the workflow relative to the README, e.g. workflow=\".github/workflows/test.yml\"")
+	}
+	job, err := getAttribute(line, "job")
+	if err != nil {
+		return "", fmt.Errorf("missing job attribute in example placeholder for %s", workflow)
+	}
+	step, _ := getAttribute(line, "step")
+	node, err := action.WorkflowSnippet(filepath.Join(filepath.Dir(d.name), workflow), job, step)
+	if err != nil {
+		return "", err
+	}
+	// Like in usage sections, the uses references of the action are shown with the version
+	if _, ok := getAttributeOk(line, "version"); ok {
+		reference, version, err := d.getUsageAttributes(line)
+		if err != nil {
+			return "", err
+		}
+		rewriteUses(node, reference, version)
+	}
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(node); err != nil {
+		return "", fmt.Errorf("failed to encode example: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return "", fmt.Errorf("failed to encode example: %w", err)
+	}
+	return codeFence(strings.TrimRight(buf.String(), "\n"), "yaml"), nil
+}
+
+// rewriteUses sets the version of every uses value that matches the reference glob.
+// Local actions of the repository (uses: ./ or ./path) are replaced with the reference, unless it is a glob.
+// Example: "./deploy" with reference "org/repo" and version "v1" -> "org/repo/deploy@v1"
+func rewriteUses(node *yaml.Node, reference string, version string) {
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			uses := node.Content[i+1]
+			if node.Content[i].Value != "uses" || uses.Kind != yaml.ScalarNode {
+				continue
+			}
+			if uses.Value == "." || strings.HasPrefix(uses.Value, "./") {
+				if !strings.ContainsAny(reference, "*?[{") {
+					name := reference
+					if path := strings.Trim(strings.TrimPrefix(uses.Value, "."), "/"); path != "" {
+						name += "/" + path
+					}
+					uses.Value = name + "@" + version
+				}
+				continue
+			}
+			name, _, _ := strings.Cut(uses.Value, "@")
+			if ok, _ := doublestar.Match(reference, name); ok {
+				uses.Value = name + "@" + version
+			}
+		}
+	}
+	for _, child := range node.Content {
+		rewriteUses(child, reference, version)
+	}
+}
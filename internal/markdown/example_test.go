@@ -0,0 +1,123 @@
+package markdown
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// exampleFiles returns the workflow next to the README of the example tests
+func exampleFiles(t *testing.T) map[string][]byte {
+	workflow, err := os.ReadFile(filepath.Join("..", "testdata", "example-workflow.yml"))
+	require.NoError(t, err)
+	return map[string][]byte{".github/workflows/test.yml": workflow}
+}
+
+func TestUpdateExamples(t *testing.T) {
+	tests := []struct {
+		name     string
+		start    string
+		expected []string
+	}{
+		{
+			name:  "step",
+			start: "<!--example workflow=\".github/workflows/test.yml\" job=\"e2e\" step=\"run-action\"-->",
+			expected: []string{
+				"```yaml",
+				"# Use the action from this commit",
+				"- id: run-action",
+				"  name: Run action",
+				"  uses: ./",
+				"  with:",
+				"    token: ${{ secrets.GITHUB_TOKEN }} # needed for the API",
+				"```",
+			},
+		},
+		{
+			name:  "job with versions",
+			start: "<!--example workflow=\".github/workflows/test.yml\" job=\"e2e\" action=\"org/repo\" version=\"v1\"-->",
+			expected: []string{
+				"```yaml",
+				"# Runs the action like a user would",
+				"e2e:",
+				"  runs-on: ubuntu-latest # the smallest runner is enough",
+				"  steps:",
+				"    - uses: actions/checkout@v4",
+				"    # Use the action from this commit",
+				"    - id: run-action",
+				"      name: Run action",
+				"      uses: org/repo@v1",
+				"      with:",
+				"        token: ${{ secrets.GITHUB_TOKEN }} # needed for the API",
+				"    - name: Deploy",
+				"      uses: org/repo/deploy@v1",
+				"    - uses: org/repo/setup@main",
+				"```",
+			},
+		},
+		{
+			name:  "glob",
+			start: "<!--example workflow=\".github/workflows/test.yml\" job=\"e2e\" step=\"Deploy\" action=\"org/repo/**\" version=\"v2\"-->",
+			expected: []string{
+				"```yaml",
+				"- name: Deploy",
+				"  uses: ./deploy",
+				"```",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// arrange
+			doc := testDoc(t, exampleFiles(t), tt.start, "<!--/example-->")
+
+			// act
+			err := doc.updateExamples()
+
+			// assert
+			assert.NoError(t, err)
+			lines := append([]string{tt.start}, tt.expected...)
+			assert.Equal(t, string(fromLines(append(lines, "<!--/example-->")...)), doc.ToString())
+		})
+	}
+}
+
+func TestRewriteUsesGlob(t *testing.T) {
+	// arrange
+	doc := testDoc(t, exampleFiles(t), "<!--example workflow=\".github/workflows/test.yml\" job=\"e2e\" action=\"org/repo/**\" version=\"v2\"-->", "<!--/example-->")
+
+	// act
+	err := doc.updateExamples()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Contains(t, doc.ToString(), "- uses: org/repo/setup@v2")
+	assert.Contains(t, doc.ToString(), "uses: ./\n")
+}
+
+func TestUpdateExamplesErrors(t *testing.T) {
+	tests := []struct {
+		start string
+		err   string
+	}{
+		{"<!--example job=\"e2e\"-->", "missing workflow attribute"},
+		{"<!--example workflow=\".github/workflows/test.yml\"-->", "missing job attribute"},
+		{"<!--example workflow=\".github/workflows/missing.yml\" job=\"e2e\"-->", "failed to read file"},
+		{"<!--example workflow=\".github/workflows/test.yml\" job=\"e2e\" version=\"v1\"-->", "failed to get attribute action"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.start, func(t *testing.T) {
+			// arrange
+			doc := testDoc(t, exampleFiles(t), tt.start, "<!--/example-->")
+
+			// act
+			err := doc.updateExamples()
+
+			// assert
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package markdown
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/reakaleek/gh-action-readme/internal/action"
+	"github.com/stretchr/testify/require"
+)
+
+// testAction returns an action named Test with the given inputs in the given order
+func testAction(inputsOrder []string, inputs action.Inputs) *action.Action {
+	return action.New("Test", "Author", "Test description.", inputs, inputsOrder, action.Outputs{}, nil)
+}
+
+// testDoc returns a README with the given lines in a temporary directory with the given files.
+// The names of the files are relative to the directory.
+func testDoc(t *testing.T, files map[string][]byte, lines ...string) Doc {
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, content, 0644))
+	}
+	return Doc{
+		name:   filepath.Join(root, "README.md"),
+		source: fromLines(lines...),
+	}
+}
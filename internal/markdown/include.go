@@ -0,0 +1,180 @@
+package markdown
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// regionStartPattern matches the start of a named region in an included file
+	// Example: # region setup
+	regionStartPattern = regexp.MustCompile(`^\s*(?:#|//)\s*region\s+(\S+)\s*$`)
+	// regionEndPattern matches the end of a region in an included file
+	// Example: # endregion
+	regionEndPattern = regexp.MustCompile(`^\s*(?:#|//)\s*endregion\b`)
+	// fenceRunPattern matches backtick runs at the start of a line that would end a code block
+	fenceRunPattern = regexp.MustCompile("(?m)^\\s*(`{3,})")
+)
+
+// languages are the code block languages of common file extensions
+var languages = map[string]string{
+	".yml":  "yaml",
+	".yaml": "yaml",
+	".json": "json",
+	".sh":   "bash",
+	".bash": "bash",
+	".ps1":  "powershell",
+	".py":   "python",
+	".js":   "javascript",
+	".mjs":  "javascript",
+	".ts":   "typescript",
+	".go":   "go",
+	".rb":   "ruby",
+	".md":   "markdown",
+	".toml": "toml",
+}
+
+// updateIncludes replaces every include section with the referenced file, or part of it, in a code block
+// Example: <!--include file=".github/workflows/example-basic.yml" region="steps" lang="yaml"-->
+func (d *Doc) updateIncludes() error {
+	sections := d.sectionsNamed(includeSectionName)
+	edits := make([]edit, 0, len(sections))
+	for _, s := range sections {
+		if !strings.Contains(d.content(s), "\n") {
+			return fmt.Errorf("include section must start and end on separate lines")
+		}
+		content, err := d.include(s.start.text)
+		if err != nil {
+			return err
+		}
+		edits = append(edits, d.sectionEdit(s, content))
+	}
+	d.apply(edits...)
+	return nil
+}
+
+func (d *Doc) include(line string) (string, error) {
+	file, err := getAttribute(line, "file")
+	if err != nil {
+		return "", fmt.Errorf("missing file attribute in include placeholder. add the path of the file relative to the README, e.g. file=\"examples/basic.yml\"")
+	}
+	data, err := os.ReadFile(filepath.Join(filepath.Dir(d.name), file))
+	if err != nil {
+		return "", fmt.Errorf("failed to read include file: %w", err)
+	}
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	lineRange, hasLines := getAttributeOk(line, "lines")
+	region, hasRegion := getAttributeOk(line, "region")
+	switch {
+	case hasLines && hasRegion:
+		return "", fmt.Errorf("include of %s can't have both lines and region", file)
+	case hasLines:
+		lines, err = selectLines(lines, lineRange)
+	case hasRegion:
+		lines, err = selectRegion(lines, region)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to include %s: %w", file, err)
+	}
+
+	lang, ok := getAttributeOk(line, "lang")
+	if !ok {
+		lang = languages[strings.ToLower(filepath.Ext(file))]
+	}
+	return codeFence(dedent(lines), lang), nil
+}
+
+// getAttributeOk returns the value of the attribute and whether it is set, also if it is set to ""
+func getAttributeOk(line string, attribute string) (string, bool) {
+	value, err := getAttribute(line, attribute)
+	return value, err == nil
+}
+
+// selectLines returns the lines in the 1-based inclusive range, e.g. "5-10", or a single line, e.g. "5"
+func selectLines(lines []string, lineRange string) ([]string, error) {
+	from, to, isRange := strings.Cut(lineRange, "-")
+	start, err := strconv.Atoi(strings.TrimSpace(from))
+	end := start
+	if err == nil && isRange {
+		end, err = strconv.Atoi(strings.TrimSpace(to))
+	}
+	if err != nil || start < 1 || end < start {
+		return nil, fmt.Errorf("invalid lines %q, expected a line or a range like 5-10", lineRange)
+	}
+	if end > len(lines) {
+		return nil, fmt.Errorf("lines %q are out of range, the file has %d lines", lineRange, len(lines))
+	}
+	return lines[start-1 : end], nil
+}
+
+// selectRegion returns the lines between "# region name" and the matching "# endregion".
+// Nested region markers are left out.
+func selectRegion(lines []string, name string) ([]string, error) {
+	var selected []string
+	depth := 0
+	for _, line := range lines {
+		if depth == 0 {
+			if match := regionStartPattern.FindStringSubmatch(line); match != nil && match[1] == name {
+				depth = 1
+			}
+			continue
+		}
+		switch {
+		case regionStartPattern.MatchString(line):
+			depth++
+			continue
+		case regionEndPattern.MatchString(line):
+			depth--
+			if depth == 0 {
+				return selected, nil
+			}
+			continue
+		}
+		selected = append(selected, line)
+	}
+	if depth > 0 {
+		return nil, fmt.Errorf("missing endregion for region %q", name)
+	}
+	return nil, fmt.Errorf("region %q not found", name)
+}
+
+// dedent removes the indentation all non-empty lines have in common
+func dedent(lines []string) string {
+	indent := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		n := len(line) - len(strings.TrimLeft(line, " \t"))
+		if indent == -1 || n < indent {
+			indent = n
+		}
+	}
+	result := make([]string, len(lines))
+	for i, line := range lines {
+		if len(line) >= indent && indent > 0 {
+			line = line[indent:]
+		}
+		result[i] = strings.TrimRight(line, " \t")
+	}
+	return strings.Join(result, "\n")
+}
+
+// codeFence renders the code in a fenced code block.
+// The fence is longer than any backtick fence in the code, so included markdown can't end the code block.
+func codeFence(code string, lang string) string {
+	length := 3
+	for _, match := range fenceRunPattern.FindAllStringSubmatch(code, -1) {
+		length = max(length, len(match[1])+1)
+	}
+	fence := strings.Repeat("`", length)
+	return fence + lang + "\n" + code + "\n" + fence
+}
@@ -0,0 +1,134 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// exampleWorkflow is an included file with a nested region
+var exampleWorkflow = []string{
+	"on: push",
+	"jobs:",
+	"  build:",
+	"    steps:",
+	"      # region steps",
+	"      - uses: actions/checkout@v4",
+	"      # region action",
+	"      - uses: org/repo@v1",
+	"        with:",
+	"          token: ${{ secrets.TOKEN }}",
+	"      # endregion",
+	"      # endregion",
+}
+
+// includeFiles are the files next to the README of the include tests
+var includeFiles = map[string][]byte{
+	"examples/basic.yml": fromLines(append(exampleWorkflow, "")...),
+	"examples/README.md": fromLines("```yaml", "on: push", "```"),
+}
+
+func TestUpdateIncludes(t *testing.T) {
+	tests := []struct {
+		name     string
+		start    string
+		expected []string
+	}{
+		{
+			name:     "file",
+			start:    "<!--include file=\"examples/basic.yml\"-->",
+			expected: append(append([]string{"```yaml"}, exampleWorkflow...), "```"),
+		},
+		{
+			name:     "lines",
+			start:    "<!--include file=\"examples/basic.yml\" lines=\"3-4\"-->",
+			expected: []string{"```yaml", "build:", "  steps:", "```"},
+		},
+		{
+			name:     "single line",
+			start:    "<!--include file=\"examples/basic.yml\" lines=\"1\" lang=\"yml\"-->",
+			expected: []string{"```yml", "on: push", "```"},
+		},
+		{
+			name:  "region",
+			start: "<!--include file=\"examples/basic.yml\" region=\"steps\"-->",
+			expected: []string{
+				"```yaml",
+				"- uses: actions/checkout@v4",
+				"- uses: org/repo@v1",
+				"  with:",
+				"    token: ${{ secrets.TOKEN }}",
+				"```",
+			},
+		},
+		{
+			name:     "nested region",
+			start:    "<!--include file=\"examples/basic.yml\" region=\"action\" lang=\"\"-->",
+			expected: []string{"```", "- uses: org/repo@v1", "  with:", "    token: ${{ secrets.TOKEN }}", "```"},
+		},
+		{
+			name:     "markdown with fences",
+			start:    "<!--include file=\"examples/README.md\"-->",
+			expected: []string{"````markdown", "```yaml", "on: push", "```", "````"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// arrange
+			doc := testDoc(t, includeFiles, tt.start, "<!--/include-->")
+
+			// act
+			err := doc.updateIncludes()
+
+			// assert
+			assert.NoError(t, err)
+			lines := append([]string{tt.start}, tt.expected...)
+			assert.Equal(t, string(fromLines(append(lines, "<!--/include-->")...)), doc.ToString())
+		})
+	}
+}
+
+func TestUpdateIncludesErrors(t *testing.T) {
+	tests := []struct {
+		start string
+		err   string
+	}{
+		{"<!--include-->", "missing file attribute"},
+		{"<!--include file=\"missing.yml\"-->", "failed to read include file"},
+		{"<!--include file=\"examples/basic.yml\" lines=\"4-3\"-->", `invalid lines "4-3"`},
+		{"<!--include file=\"examples/basic.yml\" lines=\"10-20\"-->", `lines "10-20" are out of range, the file has 12 lines`},
+		{"<!--include file=\"examples/basic.yml\" region=\"missing\"-->", `region "missing" not found`},
+		{"<!--include file=\"examples/basic.yml\" lines=\"1\" region=\"steps\"-->", "can't have both lines and region"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.start, func(t *testing.T) {
+			// arrange
+			doc := testDoc(t, includeFiles, tt.start, "<!--/include-->")
+
+			// act
+			err := doc.updateIncludes()
+
+			// assert
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestSelectRegionMissingEnd(t *testing.T) {
+	// act
+	_, err := selectRegion([]string{"// region main", "code"}, "main")
+
+	// assert
+	assert.EqualError(t, err, `missing endregion for region "main"`)
+}
+
+func TestUpdateIncludesSameLine(t *testing.T) {
+	// arrange
+	doc := Doc{source: []byte("<!--include file=\"a.yml\"--><!--/include-->")}
+
+	// act
+	err := doc.updateIncludes()
+
+	// assert
+	assert.EqualError(t, err, "include section must start and end on separate lines")
+}
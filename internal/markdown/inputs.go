@@ -0,0 +1,218 @@
+package markdown
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/reakaleek/gh-action-readme/internal/action"
+)
+
+const groupByPrefix = "prefix"
+
+// tableOptions are the attributes of the inputs and outputs placeholders
+// Example: <!--inputs columns="name,default" default-label="Default value" sort="alpha" group-by="prefix" style="list"-->
+type tableOptions struct {
+	columns      []string
+	labels       map[string]string
+	selection    action.Selection
+	groupBy      string
+	headingLevel int
+	style        string
+}
+
+func getTableOptions(line string, defaultColumns []string) (tableOptions, error) {
+	options := tableOptions{headingLevel: 3, style: styleTable}
+	options.columns, options.labels = getColumns(line, defaultColumns)
+	options.selection.Sort, _ = getAttribute(line, "sort")
+	if include, err := getAttribute(line, "include"); err == nil {
+		options.selection.Include = splitList(include)
+	}
+	if exclude, err := getAttribute(line, "exclude"); err == nil {
+		options.selection.Exclude = splitList(exclude)
+	}
+	if groupBy, err := getAttribute(line, "group-by"); err == nil {
+		if groupBy != groupByPrefix {
+			return options, fmt.Errorf("unknown group-by %q, expected %s", groupBy, groupByPrefix)
+		}
+		options.groupBy = groupBy
+	}
+	if level, err := getAttribute(line, "heading-level"); err == nil {
+		options.headingLevel, err = strconv.Atoi(level)
+		if err != nil || options.headingLevel < 1 || options.headingLevel > 6 {
+			return options, fmt.Errorf("invalid heading-level %q, expected a number between 1 and 6", level)
+		}
+	}
+	if style, err := getAttribute(line, "style"); err == nil {
+		if !slices.Contains(styles, style) {
+			return options, fmt.Errorf("unknown style %q, expected one of %s", style, strings.Join(styles, ", "))
+		}
+		options.style = style
+	}
+	if options.style != styleTable && options.style != styleHTML && !slices.Contains(options.columns, action.ColumnName) {
+		return options, fmt.Errorf("style %q needs the %s column", options.style, action.ColumnName)
+	}
+	return options, nil
+}
+
+func (d *Doc) updateInputs(a *action.Action) error {
+	return d.updateSections(d.sectionsNamed(inputsSectionName), a, inputs)
+}
+
+func inputs(s section, a *action.Action) (string, error) {
+	line := s.start.text
+	options, err := getTableOptions(line, a.DefaultInputColumns())
+	if err != nil {
+		return "", err
+	}
+	keys, err := a.SelectInputs(options.selection)
+	if err != nil {
+		return "", err
+	}
+	content, err := groupedTables(keys, options, func(keys []string) ([][]string, error) {
+		return a.GetInputsMatrixFor(keys, options.columns, options.labels)
+	})
+	if err != nil {
+		return "", err
+	}
+	if callouts, _ := getAttribute(line, "callouts"); callouts == "true" {
+		content += deprecationCallouts(a, keys)
+	}
+	return content, nil
+}
+
+// deprecationCallouts renders a warning callout for each deprecated input of the table
+func deprecationCallouts(a *action.Action, keys []string) string {
+	var sb strings.Builder
+	for _, key := range keys {
+		if !a.Inputs[key].IsDeprecated() {
+			continue
+		}
+		message := strings.TrimSpace(a.Inputs[key].DeprecationMessage)
+		sb.WriteString("\n")
+		sb.WriteString(callout(calloutWarning, fmt.Sprintf("The `%s` input is deprecated. %s", key, message)))
+	}
+	return sb.String()
+}
+
+func (d *Doc) updateOutputs(a *action.Action) error {
+	return d.updateSections(d.sectionsNamed(outputsSectionName), a, outputs)
+}
+
+func outputs(s section, a *action.Action) (string, error) {
+	options, err := getTableOptions(s.start.text, action.DefaultOutputColumns)
+	if err != nil {
+		return "", err
+	}
+	keys, err := a.SelectOutputs(options.selection)
+	if err != nil {
+		return "", err
+	}
+	return groupedTables(keys, options, func(keys []string) ([][]string, error) {
+		return a.GetOutputsMatrixFor(keys, options.columns, options.labels)
+	})
+}
+
+// groupedTables renders one table, or one table per group under a generated heading if grouping is enabled.
+// The tables are rendered in the configured style.
+func groupedTables(keys []string, options tableOptions, matrix func(keys []string) ([][]string, error)) (string, error) {
+	if options.groupBy == "" {
+		m, err := matrix(keys)
+		if err != nil {
+			return "", err
+		}
+		return layout(m, options, options.headingLevel), nil
+	}
+	var sb strings.Builder
+	for _, g := range groupKeysByPrefix(keys) {
+		m, err := matrix(g.keys)
+		if err != nil {
+			return "", err
+		}
+		if sb.Len() > 0 {
+			sb.WriteString("\n")
+		}
+		level := options.headingLevel
+		if g.prefix != "" {
+			sb.WriteString(fmt.Sprintf("%s `%s*`\n\n", strings.Repeat("#", options.headingLevel), g.prefix))
+			// Headings of the entries are nested under the heading of the group
+			level = min(level+1, 6)
+		}
+		sb.WriteString(layout(m, options, level))
+	}
+	return sb.String(), nil
+}
+
+type group struct {
+	// prefix includes the separator, e.g. "cache-" or "cache_"
+	prefix string
+	keys   []string
+}
+
+// groupKeysByPrefix groups the keys by the part up to the first "-" or "_", including the separator.
+// So "cache-key" and "cache_path" are in different groups.
+// Keys that do not share their prefix with any other key are returned in a first group without prefix.
+// Groups are ordered by their first key.
+func groupKeysByPrefix(keys []string) []group {
+	counts := make(map[string]int)
+	for _, key := range keys {
+		counts[prefixOf(key)]++
+	}
+	ungrouped := group{}
+	var groups []group
+	indices := make(map[string]int)
+	for _, key := range keys {
+		prefix := prefixOf(key)
+		if prefix == "" || counts[prefix] < 2 {
+			ungrouped.keys = append(ungrouped.keys, key)
+			continue
+		}
+		i, ok := indices[prefix]
+		if !ok {
+			i = len(groups)
+			indices[prefix] = i
+			groups = append(groups, group{prefix: prefix})
+		}
+		groups[i].keys = append(groups[i].keys, key)
+	}
+	if len(ungrouped.keys) > 0 {
+		groups = append([]group{ungrouped}, groups...)
+	}
+	return groups
+}
+
+func prefixOf(key string) string {
+	i := strings.IndexAny(key, "-_")
+	if i <= 0 {
+		return ""
+	}
+	return key[:i+1]
+}
+
+// getColumns returns the columns and header labels configured on a placeholder
+// Example: <!--inputs columns="name,description,default" default-label="Default value"-->
+func getColumns(line string, defaultColumns []string) ([]string, map[string]string) {
+	columns := defaultColumns
+	if value, err := getAttribute(line, "columns"); err == nil {
+		columns = splitList(value)
+	}
+	labels := make(map[string]string)
+	for _, column := range columns {
+		if label, err := getAttribute(line, column+"-label"); err == nil {
+			labels[column] = label
+		}
+	}
+	return columns, labels
+}
+
+// splitList splits a comma-separated attribute value
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,148 @@
+package markdown
+
+import (
+	"github.com/reakaleek/gh-action-readme/internal/action"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestGetColumns(t *testing.T) {
+	// arrange
+	line := "<!--inputs columns=\"name, default,description\" default-label=\"Default value\"-->"
+
+	// act
+	columns, labels := getColumns(line, []string{"name"})
+
+	// assert
+	assert.Equal(t, []string{"name", "default", "description"}, columns)
+	assert.Equal(t, map[string]string{"default": "Default value"}, labels)
+}
+
+func TestGetColumnsDefault(t *testing.T) {
+	// act
+	columns, labels := getColumns("<!--inputs-->", []string{"name", "description"})
+
+	// assert
+	assert.Equal(t, []string{"name", "description"}, columns)
+	assert.Empty(t, labels)
+}
+
+func TestGetTableOptions(t *testing.T) {
+	// arrange
+	line := "<!--inputs sort=\"required-first\" include=\"cache-*, token\" exclude=\"cache-key\" group-by=\"prefix\" heading-level=\"4\"-->"
+
+	// act
+	options, err := getTableOptions(line, []string{"name"})
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "required-first", options.selection.Sort)
+	assert.Equal(t, []string{"cache-*", "token"}, options.selection.Include)
+	assert.Equal(t, []string{"cache-key"}, options.selection.Exclude)
+	assert.Equal(t, "prefix", options.groupBy)
+	assert.Equal(t, 4, options.headingLevel)
+}
+
+func TestGetTableOptionsInvalid(t *testing.T) {
+	// act
+	_, groupByErr := getTableOptions("<!--inputs group-by=\"suffix\"-->", nil)
+	_, headingErr := getTableOptions("<!--inputs group-by=\"prefix\" heading-level=\"7\"-->", nil)
+
+	// assert
+	assert.EqualError(t, groupByErr, `unknown group-by "suffix", expected prefix`)
+	assert.EqualError(t, headingErr, `invalid heading-level "7", expected a number between 1 and 6`)
+}
+
+func TestGetTableOptionsStyle(t *testing.T) {
+	// act
+	options, err := getTableOptions("<!--inputs style=\"list\"-->", []string{"name"})
+	_, styleErr := getTableOptions("<!--inputs style=\"cards\"-->", []string{"name"})
+	_, columnErr := getTableOptions("<!--inputs style=\"headings\" columns=\"description\"-->", nil)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, styleList, options.style)
+	assert.EqualError(t, styleErr, `unknown style "cards", expected one of table, list, headings, html`)
+	assert.EqualError(t, columnErr, `style "headings" needs the name column`)
+}
+
+func TestGroupKeysByPrefix(t *testing.T) {
+	// act
+	groups := groupKeysByPrefix([]string{"token", "cache-key", "debug", "cache-path", "go_version", "go_cache", "cache_dir"})
+
+	// assert
+	assert.Equal(t, []group{
+		{prefix: "", keys: []string{"token", "debug", "cache_dir"}},
+		{prefix: "cache-", keys: []string{"cache-key", "cache-path"}},
+		{prefix: "go_", keys: []string{"go_version", "go_cache"}},
+	}, groups)
+}
+
+func TestInputsCalloutsOfExcludedInputs(t *testing.T) {
+	// arrange
+	doc := Doc{
+		source: fromLines(
+			"<!--inputs columns=\"name\" exclude=\"github-token\" callouts=\"true\"-->",
+			"<!--/inputs-->",
+		),
+	}
+	a := &action.Action{
+		Inputs: action.Inputs{
+			"token":        {Description: "The token"},
+			"github-token": {Description: "The GitHub token", DeprecationMessage: "Use token instead."},
+			"debug":        {Description: "Debug logging", DeprecationMessage: "Use RUNNER_DEBUG instead."},
+		},
+		InputsOrder: []string{"token", "github-token", "debug"},
+	}
+
+	// act
+	err := doc.updateInputs(a)
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, string(fromLines(
+		"<!--inputs columns=\"name\" exclude=\"github-token\" callouts=\"true\"-->",
+		"| Name    |",
+		"|---------|",
+		"| `token` |",
+		"| `debug` |",
+		"",
+		"> [!WARNING]",
+		"> The `debug` input is deprecated. Use RUNNER_DEBUG instead.",
+		"<!--/inputs-->",
+	)), doc.ToString())
+}
+
+func TestGroupedTablesHeadingSeparator(t *testing.T) {
+	// arrange
+	doc := Doc{
+		source: fromLines(
+			"<!--inputs columns=\"name\" group-by=\"prefix\"-->",
+			"<!--/inputs-->",
+		),
+	}
+	a := &action.Action{
+		Inputs: action.Inputs{
+			"cache_key":  {Description: "The cache key"},
+			"cache_path": {Description: "The cache path"},
+		},
+		InputsOrder: []string{"cache_key", "cache_path"},
+	}
+
+	// act
+	err := doc.updateInputs(a)
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, string(fromLines(
+		"<!--inputs columns=\"name\" group-by=\"prefix\"-->",
+		"### `cache_*`",
+		"",
+		"| Name         |",
+		"|--------------|",
+		"| `cache_key`  |",
+		"| `cache_path` |",
+		"<!--/inputs-->",
+	)), doc.ToString())
+}
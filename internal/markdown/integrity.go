@@ -0,0 +1,148 @@
+package markdown
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// legacySections are the placeholders of the first releases, which added the end marker of a section if it was missing
+var legacySections = []string{nameSectionName, descriptionSectionName, inputsSectionName, outputsSectionName}
+
+// closeSections adds the end marker of a legacy section after the line of its start marker,
+// if the document has a single start marker and no end marker with its name.
+// Example: "<!--inputs-->\n\n## Outputs" -> "<!--inputs-->\n<!--/inputs-->\n\n## Outputs"
+func (d *Doc) closeSections() {
+	starts := make(map[string][]marker)
+	ends := make(map[string]int)
+	for _, m := range d.markers() {
+		if m.closing {
+			ends[m.name]++
+		} else {
+			starts[m.name] = append(starts[m.name], m)
+		}
+	}
+	var edits []edit
+	for _, m := range d.markers() {
+		if m.closing || !slices.Contains(legacySections, m.name) || len(starts[m.name]) != 1 || ends[m.name] > 0 {
+			continue
+		}
+		prefix := containerPrefix(string(d.source[lineStart(d.source, m.start):m.start]))
+		end := lineEnd(d.source, m.end)
+		edits = append(edits, edit{end, end, "\n" + prefix + closingTag(m.name)})
+	}
+	if len(edits) == 0 {
+		return
+	}
+	d.apply(edits...)
+	// The index doesn't know the new end markers
+	d.sections = nil
+}
+
+// checkMarkers reports unbalanced, nested and misplaced placeholders with their line numbers.
+// Every start marker needs an end marker. If the same start marker follows and is closed, the start
+// marker is reported as opened again, since the end marker was most likely forgotten.
+// Placeholders in code blocks are ignored, but mentioned if they would close or open a broken section.
+func (d *Doc) checkMarkers() error {
+	var markers []marker
+	for _, m := range d.markers() {
+		if slices.Contains(placeholders, m.name) {
+			markers = append(markers, m)
+		}
+	}
+	// ends holds for every start marker the index of its end marker, if the next marker with the same name is an end marker
+	ends := make(map[int]int)
+	for i, m := range markers {
+		if m.closing {
+			continue
+		}
+		for j := i + 1; j < len(markers); j++ {
+			if markers[j].name == m.name {
+				if markers[j].closing {
+					ends[i] = j
+				}
+				break
+			}
+		}
+	}
+	// nestedEnds are the end markers of nested sections, they are reported with their start marker
+	nestedEnds := make(map[int]bool)
+
+	var issues []error
+	report := func(m marker, format string, args ...any) {
+		issues = append(issues, fmt.Errorf("%s:%d: %s", d.name, lineNumber(d.source, m.start), fmt.Sprintf(format, args...)))
+	}
+	var open *marker
+	for i, m := range markers {
+		switch {
+		case m.closing && open != nil && open.name == m.name:
+			open = nil
+		case m.closing && nestedEnds[i]:
+		case m.closing:
+			report(m, "%s has no opening %s%s", closingTag(m.name), openingTag(m.name), d.ignoredMarkerHint(m.name, false))
+		case open != nil:
+			report(m, "%s is nested in the %s section opened on line %d, sections can't be nested", openingTag(m.name), open.name, lineNumber(d.source, open.start))
+			if j, ok := ends[i]; ok {
+				nestedEnds[j] = true
+			}
+		case hasEnd(ends, i):
+			open = &markers[i]
+		default:
+			if next := nextStart(markers[i+1:], m.name); next != nil && next.text == m.text && closedLater(markers, next) {
+				report(m, "%s is opened again on line %d before %s", d.source[m.start:m.end], lineNumber(d.source, next.start), closingTag(m.name))
+			} else {
+				report(m, "missing end comment for %s section. add %s to the end of the section%s", m.name, closingTag(m.name), d.ignoredMarkerHint(m.name, true))
+			}
+		}
+	}
+	return errors.Join(issues...)
+}
+
+func hasEnd(ends map[int]int, i int) bool {
+	_, ok := ends[i]
+	return ok
+}
+
+// nextStart returns the next start marker with the given name
+func nextStart(markers []marker, name string) *marker {
+	for i, m := range markers {
+		if m.name == name && !m.closing {
+			return &markers[i]
+		}
+	}
+	return nil
+}
+
+// closedLater reports whether an end marker with the name of start follows start
+func closedLater(markers []marker, start *marker) bool {
+	for _, m := range markers {
+		if m.start > start.start && m.name == start.name && m.closing {
+			return true
+		}
+	}
+	return false
+}
+
+// ignoredMarkerHint mentions a marker with the given name in a code block, which is a common reason for a broken section
+func (d *Doc) ignoredMarkerHint(name string, closing bool) string {
+	for _, f := range d.index().fences {
+		for _, loc := range commentPattern.FindAllStringIndex(f.content, -1) {
+			m, ok := parseMarker(f.content[loc[0]:loc[1]])
+			if !ok || m.name != name || m.closing != closing {
+				continue
+			}
+			line := lineNumber(d.source, f.start) + strings.Count(f.content[:loc[0]], "\n")
+			return fmt.Sprintf(" (the %s on line %d is in a code block and ignored)", m.text, line)
+		}
+	}
+	return ""
+}
+
+func openingTag(name string) string {
+	return "<!--" + name + "-->"
+}
+
+func closingTag(name string) string {
+	return "<!--/" + name + "-->"
+}
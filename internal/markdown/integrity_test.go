@@ -0,0 +1,158 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckMarkers(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		err   string
+	}{
+		{
+			name:  "balanced",
+			lines: []string{"# <!--name--><!--/name-->", "<!--inputs-->", "<!--/inputs-->", "<!--outputs-->", "<!--/outputs-->"},
+		},
+		{
+			name:  "missing close of generated section",
+			lines: []string{"<!--inputs-->", "text"},
+			err:   "README.md:1: missing end comment for inputs section. add <!--/inputs--> to the end of the section",
+		},
+		{
+			name:  "missing close before start with different attributes",
+			lines: []string{"<!--inputs action=\"./a\"-->", "<!--inputs action=\"./b\"-->", "<!--/inputs-->"},
+			err:   "README.md:1: missing end comment for inputs section. add <!--/inputs--> to the end of the section",
+		},
+		{
+			name:  "unknown comments",
+			lines: []string{"<!--/note-->", "<!-- title -->"},
+		},
+		{
+			name:  "missing close",
+			lines: []string{"<!--usage action=\"org/repo\" version=\"v1\"-->", "```yaml", "- uses: org/repo@v1", "```"},
+			err:   "README.md:1: missing end comment for usage section. add <!--/usage--> to the end of the section",
+		},
+		{
+			name:  "duplicate open",
+			lines: []string{"<!--inputs-->", "text", "<!--inputs-->", "<!--/inputs-->"},
+			err:   "README.md:1: <!--inputs--> is opened again on line 3 before <!--/inputs-->",
+		},
+		{
+			name:  "nested",
+			lines: []string{"<!--description-->", "<!--name--><!--/name-->", "<!--/description-->"},
+			err:   "README.md:2: <!--name--> is nested in the description section opened on line 1, sections can't be nested",
+		},
+		{
+			name:  "close before open",
+			lines: []string{"<!--/outputs-->", "<!--outputs-->", "<!--/outputs-->"},
+			err:   "README.md:1: <!--/outputs--> has no opening <!--outputs-->",
+		},
+		{
+			name: "close in code fence",
+			lines: []string{
+				"<!--usage action=\"org/repo\" version=\"v1\"-->",
+				"```yaml",
+				"- uses: org/repo@v1",
+				"<!--/usage-->",
+				"```",
+			},
+			err: "README.md:1: missing end comment for usage section. add <!--/usage--> to the end of the section (the <!--/usage--> on line 4 is in a code block and ignored)",
+		},
+		{
+			name:  "several problems",
+			lines: []string{"<!--/inputs-->", "<!--usage-example action=\"org/repo\" version=\"v1\"-->"},
+			err: "README.md:1: <!--/inputs--> has no opening <!--inputs-->\n" +
+				"README.md:2: missing end comment for usage-example section. add <!--/usage-example--> to the end of the section",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// arrange
+			doc := Doc{name: "README.md", source: fromLines(tt.lines...)}
+
+			// act
+			err := doc.checkMarkers()
+
+			// assert
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestUpdateChecksMarkers(t *testing.T) {
+	// arrange
+	source := fromLines("<!--/description-->", "<!--description-->")
+	doc := Doc{name: "README.md", source: source}
+
+	// act
+	err := doc.Update(nil)
+
+	// assert
+	assert.EqualError(t, err, "README.md:1: <!--/description--> has no opening <!--description-->\n"+
+		"README.md:2: missing end comment for description section. add <!--/description--> to the end of the section")
+	assert.Equal(t, string(source), doc.ToString())
+}
+
+func TestUpdateChecksLoneEndMarker(t *testing.T) {
+	// arrange
+	source := fromLines("# Title", "<!--/inputs-->")
+	doc := Doc{name: "README.md", source: source}
+
+	// act
+	err := doc.Update(nil)
+
+	// assert
+	assert.EqualError(t, err, "README.md:2: <!--/inputs--> has no opening <!--inputs-->")
+	assert.Equal(t, string(source), doc.ToString())
+}
+
+func TestCloseSections(t *testing.T) {
+	tests := []struct {
+		name     string
+		lines    []string
+		expected []string
+		err      string
+	}{
+		{
+			name:     "legacy sections",
+			lines:    []string{"# <!--name-->", "<!--inputs-->", "", "> <!--outputs-->", "## Usage"},
+			expected: []string{"# <!--name-->", "<!--/name-->", "<!--inputs-->", "<!--/inputs-->", "", "> <!--outputs-->", "> <!--/outputs-->", "## Usage"},
+		},
+		{
+			name:     "hand-written section",
+			lines:    []string{"<!--usage action=\"org/repo\" version=\"v1\"-->", "text"},
+			expected: []string{"<!--usage action=\"org/repo\" version=\"v1\"-->", "text"},
+			err:      "README.md:1: missing end comment for usage section. add <!--/usage--> to the end of the section",
+		},
+		{
+			name:     "end marker of another section",
+			lines:    []string{"<!--inputs-->", "<!--inputs-->", "<!--/inputs-->"},
+			expected: []string{"<!--inputs-->", "<!--inputs-->", "<!--/inputs-->"},
+			err:      "README.md:1: <!--inputs--> is opened again on line 2 before <!--/inputs-->",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// arrange
+			doc := Doc{name: "README.md", source: fromLines(tt.lines...)}
+
+			// act
+			doc.closeSections()
+
+			// assert
+			assert.Equal(t, string(fromLines(tt.expected...)), doc.ToString())
+			if tt.err == "" {
+				assert.NoError(t, doc.checkMarkers())
+			} else {
+				assert.EqualError(t, doc.checkMarkers(), tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,229 @@
+package markdown
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// DefaultContext is the number of unchanged lines around the changes of a hunk
+const DefaultContext = 3
+
+// ANSI colors of the pretty diff
+const (
+	ansiBold  = "\x1b[1m"
+	ansiRed   = "\x1b[31m"
+	ansiGreen = "\x1b[32m"
+	ansiCyan  = "\x1b[36m"
+	ansiReset = "\x1b[0m"
+)
+
+// diffLine is a line of a line-based diff.
+// op is ' ' for an unchanged line, '-' for a removed line and '+' for an added line.
+// text includes the line break, unless it is the last line of a file without a trailing line break.
+type diffLine struct {
+	op   byte
+	text string
+}
+
+// DiffResult is the unified diff of two documents
+type DiffResult struct {
+	// PrettyDiff is the patch colored for the terminal
+	PrettyDiff string
+	Patch      string
+	HasDiff    bool
+}
+
+// Diff returns the unified diff from the document to the given document with DefaultContext lines of context
+func (d *Doc) Diff(doc *Doc) DiffResult {
+	return d.DiffContext(doc, DefaultContext)
+}
+
+// DiffContext returns the unified diff from the document to the given document with the given number of context lines
+func (d *Doc) DiffContext(doc *Doc, context int) DiffResult {
+	patch := d.Patch(doc, context)
+	return DiffResult{
+		PrettyDiff: colorize(patch),
+		Patch:      patch,
+		HasDiff:    patch != "",
+	}
+}
+
+// Patch returns the unified diff from the document to the given document, or an empty string if they are equal.
+// The paths in the headers are prefixed with a/ and b/ like the patches of git, so the patch can be applied with
+// git apply. An empty document is /dev/null, the patch creates the file then.
+func (d *Doc) Patch(doc *Doc, context int) string {
+	from := "a/" + patchPath(d.name)
+	if len(d.source) == 0 {
+		from = "/dev/null"
+	}
+	return unifiedDiff(from, "b/"+patchPath(doc.name), d.ToString(), doc.ToString(), context)
+}
+
+// colorize colors the headers, hunk headers, removed and added lines of a patch.
+// The lines before the first hunk are the headers, a removed line can start with "--- " as well.
+func colorize(patch string) string {
+	var b strings.Builder
+	header := true
+	for _, line := range strings.SplitAfter(patch, "\n") {
+		text := strings.TrimSuffix(line, "\n")
+		color := ""
+		switch {
+		case header && !strings.HasPrefix(text, "@@"):
+			color = ansiBold
+		case strings.HasPrefix(text, "@@"):
+			header = false
+			color = ansiCyan
+		case strings.HasPrefix(text, "-"):
+			color = ansiRed
+		case strings.HasPrefix(text, "+"):
+			color = ansiGreen
+		}
+		if color == "" || text == "" {
+			b.WriteString(line)
+			continue
+		}
+		b.WriteString(color + text + ansiReset + line[len(text):])
+	}
+	return b.String()
+}
+
+// FirstChangedLine returns the first line of the document that differs from the given document, or 0 if they are equal.
+// Lines added after the end of the document are reported on its last line.
+func (d *Doc) FirstChangedLine(doc *Doc) int {
+	lines := diffLines(d.ToString(), doc.ToString())
+	count := 0
+	for _, l := range lines {
+		if l.op != '+' {
+			count++
+		}
+	}
+	line := 1
+	for _, l := range lines {
+		switch {
+		case l.op == ' ':
+			line++
+		case l.op == '-' || line <= count:
+			return line
+		default:
+			return max(1, count)
+		}
+	}
+	return 0
+}
+
+func patchPath(name string) string {
+	return filepath.ToSlash(filepath.Clean(name))
+}
+
+// unifiedDiff returns the unified diff of two texts with the given number of context lines around the changes
+func unifiedDiff(fromName string, toName string, from string, to string, context int) string {
+	lines := diffLines(from, to)
+	var b strings.Builder
+	for _, hunk := range hunks(lines, context) {
+		if b.Len() == 0 {
+			fmt.Fprintf(&b, "--- %s\n+++ %s\n", fromName, toName)
+		}
+		writeHunk(&b, lines, hunk[0], hunk[1])
+	}
+	return b.String()
+}
+
+// diffLines compares the texts line by line
+func diffLines(from string, to string) []diffLine {
+	dmp := diffmatchpatch.New()
+	fromChars, toChars, lineArray := dmp.DiffLinesToChars(from, to)
+	diffs := dmp.DiffCharsToLines(dmp.DiffMain(fromChars, toChars, false), lineArray)
+	var lines []diffLine
+	for _, diff := range diffs {
+		op := byte(' ')
+		switch diff.Type {
+		case diffmatchpatch.DiffDelete:
+			op = '-'
+		case diffmatchpatch.DiffInsert:
+			op = '+'
+		}
+		for _, line := range strings.SplitAfter(diff.Text, "\n") {
+			if line != "" {
+				lines = append(lines, diffLine{op, line})
+			}
+		}
+	}
+	return lines
+}
+
+// hunks returns the start and end index of the lines of each hunk.
+// Changes that are at most twice the context apart are in the same hunk.
+func hunks(lines []diffLine, context int) [][2]int {
+	var result [][2]int
+	for i := 0; i < len(lines); i++ {
+		if lines[i].op == ' ' {
+			continue
+		}
+		start := max(0, i-context)
+		end := i + 1
+		for end < len(lines) {
+			if lines[end].op != ' ' {
+				end++
+				continue
+			}
+			next := end
+			for next < len(lines) && lines[next].op == ' ' {
+				next++
+			}
+			if next == len(lines) || next-end > 2*context {
+				break
+			}
+			end = next
+		}
+		end = min(len(lines), end+context)
+		result = append(result, [2]int{start, end})
+		i = end - 1
+	}
+	return result
+}
+
+// writeHunk writes the @@ header and the lines of a hunk
+func writeHunk(b *strings.Builder, lines []diffLine, start int, end int) {
+	fromStart, toStart := 1, 1
+	for _, line := range lines[:start] {
+		if line.op != '+' {
+			fromStart++
+		}
+		if line.op != '-' {
+			toStart++
+		}
+	}
+	fromCount, toCount := 0, 0
+	for _, line := range lines[start:end] {
+		if line.op != '+' {
+			fromCount++
+		}
+		if line.op != '-' {
+			toCount++
+		}
+	}
+	fmt.Fprintf(b, "@@ -%s +%s @@\n", hunkRange(fromStart, fromCount), hunkRange(toStart, toCount))
+	for _, line := range lines[start:end] {
+		b.WriteByte(line.op)
+		b.WriteString(line.text)
+		if !strings.HasSuffix(line.text, "\n") {
+			b.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+// hunkRange formats the start line and number of lines of a hunk.
+// An empty range starts at the line before the hunk.
+func hunkRange(start int, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", start-1)
+	case 1:
+		return fmt.Sprintf("%d", start)
+	default:
+		return fmt.Sprintf("%d,%d", start, count)
+	}
+}
@@ -0,0 +1,139 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		from     string
+		to       string
+		expected string
+	}{
+		{
+			name: "equal",
+			from: "a\nb\n",
+			to:   "a\nb\n",
+		},
+		{
+			name: "changed line with context",
+			from: "1\n2\n3\n4\n5\n6\n7\n8\n",
+			to:   "1\n2\n3\n4\nfive\n6\n7\n8\n",
+			expected: "--- a/README.md\n+++ b/README.md\n" +
+				"@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n",
+		},
+		{
+			name: "separate hunks",
+			from: "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n",
+			to:   "one\n2\n3\n4\n5\n6\n7\n8\n9\nten\n",
+			expected: "--- a/README.md\n+++ b/README.md\n" +
+				"@@ -1,4 +1,4 @@\n-1\n+one\n 2\n 3\n 4\n" +
+				"@@ -7,4 +7,4 @@\n 7\n 8\n 9\n-10\n+ten\n",
+		},
+		{
+			name: "close changes share a hunk",
+			from: "1\n2\n3\n4\n5\n6\n7\n8\n",
+			to:   "one\n2\n3\n4\n5\n6\n7\neight\n",
+			expected: "--- a/README.md\n+++ b/README.md\n" +
+				"@@ -1,8 +1,8 @@\n-1\n+one\n 2\n 3\n 4\n 5\n 6\n 7\n-8\n+eight\n",
+		},
+		{
+			name: "insertion",
+			from: "a\nc\n",
+			to:   "a\nb\nc\n",
+			expected: "--- a/README.md\n+++ b/README.md\n" +
+				"@@ -1,2 +1,3 @@\n a\n+b\n c\n",
+		},
+		{
+			name: "no newline at end of file",
+			from: "a\nb",
+			to:   "a\nb\n",
+			expected: "--- a/README.md\n+++ b/README.md\n" +
+				"@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+b\n",
+		},
+		{
+			name: "new file",
+			from: "",
+			to:   "a\n",
+			expected: "--- a/README.md\n+++ b/README.md\n" +
+				"@@ -0,0 +1 @@\n+a\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// act
+			diff := unifiedDiff("a/README.md", "b/README.md", tt.from, tt.to, 3)
+
+			// assert
+			assert.Equal(t, tt.expected, diff)
+		})
+	}
+}
+
+func TestPatch(t *testing.T) {
+	// arrange
+	empty := NewEmptyDoc("docs/README.md")
+	doc := &Doc{name: "docs/README.md", source: []byte("# Title\n")}
+
+	// act
+	patch := empty.Patch(doc, DefaultContext)
+
+	// assert
+	assert.Equal(t, "--- /dev/null\n+++ b/docs/README.md\n@@ -0,0 +1 @@\n+# Title\n", patch)
+}
+
+func TestFirstChangedLine(t *testing.T) {
+	tests := []struct {
+		name     string
+		from     string
+		to       string
+		expected int
+	}{
+		{name: "equal", from: "a\nb\n", to: "a\nb\n", expected: 0},
+		{name: "changed line", from: "a\nb\nc\n", to: "a\nB\nc\n", expected: 2},
+		{name: "inserted line", from: "a\nc\n", to: "a\nb\nc\n", expected: 2},
+		{name: "appended line", from: "a\nb\n", to: "a\nb\nc\n", expected: 2},
+		{name: "empty document", from: "", to: "a\n", expected: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// arrange
+			from := &Doc{name: "README.md", source: []byte(tt.from)}
+			to := &Doc{name: "README.md", source: []byte(tt.to)}
+
+			// act
+			line := from.FirstChangedLine(to)
+
+			// assert
+			assert.Equal(t, tt.expected, line)
+		})
+	}
+}
+
+func TestDiffContext(t *testing.T) {
+	// arrange
+	doc := &Doc{name: "README.md", source: []byte("1\n2\n3\n4\n5\n")}
+	other := &Doc{name: "README.md", source: []byte("1\n2\nthree\n4\n5\n")}
+
+	// act
+	diff := doc.DiffContext(other, 1)
+
+	// assert
+	assert.True(t, diff.HasDiff)
+	assert.Equal(t, "--- a/README.md\n+++ b/README.md\n@@ -2,3 +2,3 @@\n 2\n-3\n+three\n 4\n", diff.Patch)
+}
+
+func TestColorize(t *testing.T) {
+	// arrange
+	patch := "--- a/README.md\n+++ b/README.md\n@@ -1,2 +1,2 @@\n text\n---- rule\n+++ rule\n"
+
+	// act
+	pretty := colorize(patch)
+
+	// assert
+	assert.Equal(t, "\x1b[1m--- a/README.md\x1b[0m\n\x1b[1m+++ b/README.md\x1b[0m\n\x1b[36m@@ -1,2 +1,2 @@\x1b[0m\n"+
+		" text\n\x1b[31m---- rule\x1b[0m\n\x1b[32m+++ rule\x1b[0m\n", pretty)
+}
@@ -0,0 +1,382 @@
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+var (
+	markdownParser   = goldmark.New(goldmark.WithExtensions(extension.GFM)).Parser()
+	commentPattern   = regexp.MustCompile(`(?s)<!--.*?-->`)
+	markerPattern    = regexp.MustCompile(`^<!--\s*(/)?\s*([\w-]+)((?:\s+[\w-]+="[^"]*")*)\s*-->$`)
+	attributePattern = regexp.MustCompile(`\s([\w-]+)="([^"]*)"`)
+)
+
+// marker is a placeholder comment that starts or ends a section
+// Example: <!--inputs columns="name,description"--> or <!--/inputs-->
+type marker struct {
+	name    string
+	closing bool
+	// text is the whole comment with the default attributes of the settings, it is used to read the attributes
+	text string
+	// start and end are the byte offsets of the comment in the source
+	start int
+	end   int
+}
+
+// section is a start marker and its end marker.
+// end is nil if the section has not been closed yet.
+type section struct {
+	start marker
+	end   *marker
+}
+
+// parseMarker returns the marker of an HTML comment, if the comment is a placeholder
+func parseMarker(comment string) (marker, bool) {
+	match := markerPattern.FindStringSubmatch(comment)
+	if match == nil {
+		return marker{}, false
+	}
+	return marker{
+		name:    match[2],
+		closing: match[1] != "",
+		text:    comment,
+	}, true
+}
+
+// sectionIndex holds the placeholder markers and fenced code blocks of the document in order of appearance.
+// It is built by parsing the document once and kept up to date by the edits of the sections,
+// so finding a section neither parses nor scans the document again.
+type sectionIndex struct {
+	markers []marker
+	fences  []fence
+}
+
+// fence is a fenced code block
+type fence struct {
+	lang    string
+	content string
+	// start is the offset of the first line of the content
+	start int
+}
+
+// edit replaces the source between start and end with replacement
+type edit struct {
+	start       int
+	end         int
+	replacement string
+}
+
+func (d *Doc) parse() ast.Node {
+	return markdownParser.Parse(text.NewReader(d.source), parser.WithContext(parser.NewContext()))
+}
+
+// index returns the section index, the document is only parsed if the index is not up to date
+func (d *Doc) index() *sectionIndex {
+	if d.sections == nil {
+		d.sections = d.buildIndex()
+	}
+	return d.sections
+}
+
+// buildIndex parses the document and collects the markers and fenced code blocks.
+// Only HTML nodes are considered for markers, comments in code blocks and code spans are ignored.
+func (d *Doc) buildIndex() *sectionIndex {
+	index := &sectionIndex{}
+	_ = ast.Walk(d.parse(), func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n := n.(type) {
+		case *ast.HTMLBlock:
+			if n.Lines().Len() == 0 {
+				return ast.WalkContinue, nil
+			}
+			start, stop := n.Lines().At(0).Start, n.Lines().At(n.Lines().Len()-1).Stop
+			if n.HasClosure() {
+				stop = n.ClosureLine.Stop
+			}
+			index.markers = append(index.markers, d.markersBetween(start, stop)...)
+		case *ast.RawHTML:
+			if n.Segments.Len() == 0 {
+				return ast.WalkContinue, nil
+			}
+			index.markers = append(index.markers, d.markersBetween(n.Segments.At(0).Start, n.Segments.At(n.Segments.Len()-1).Stop)...)
+		case *ast.FencedCodeBlock:
+			if n.Lines().Len() == 0 {
+				return ast.WalkContinue, nil
+			}
+			var content bytes.Buffer
+			for i := 0; i < n.Lines().Len(); i++ {
+				line := n.Lines().At(i)
+				content.Write(line.Value(d.source))
+			}
+			index.fences = append(index.fences, fence{
+				lang:    string(n.Language(d.source)),
+				content: content.String(),
+				start:   n.Lines().At(0).Start,
+			})
+		}
+		return ast.WalkContinue, nil
+	})
+	return index
+}
+
+// markers returns all placeholder comments of the document in order of appearance
+func (d *Doc) markers() []marker {
+	return d.index().markers
+}
+
+// fencesBetween returns the fenced code blocks whose content starts between the offsets start and end
+func (d *Doc) fencesBetween(start int, end int) []fence {
+	var fences []fence
+	for _, f := range d.index().fences {
+		if f.start > start && f.start < end {
+			fences = append(fences, f)
+		}
+	}
+	return fences
+}
+
+func (d *Doc) markersBetween(start int, stop int) []marker {
+	var markers []marker
+	for _, loc := range commentPattern.FindAllIndex(d.source[start:stop], -1) {
+		m, ok := parseMarker(string(d.source[start+loc[0] : start+loc[1]]))
+		if !ok {
+			continue
+		}
+		m.start, m.end = start+loc[0], start+loc[1]
+		m = d.settings.withDefaults(m)
+		markers = append(markers, m)
+	}
+	return markers
+}
+
+// firstSection returns the first section with the given name, ok is false if there is none or it has no end marker.
+func (d *Doc) firstSection(name string) (section, bool) {
+	sections := d.sectionsNamed(name)
+	if len(sections) == 0 || sections[0].end == nil {
+		return section{}, false
+	}
+	return sections[0], true
+}
+
+// sectionsNamed returns all sections with the given name in order of appearance.
+// A start marker that is followed by another start marker before an end marker is a section without end marker.
+func (d *Doc) sectionsNamed(name string) []section {
+	var sections []section
+	open := -1
+	for _, m := range d.markers() {
+		if m.name != name {
+			continue
+		}
+		if !m.closing {
+			sections = append(sections, section{start: m})
+			open = len(sections) - 1
+		} else if open != -1 {
+			end := m
+			sections[open].end = &end
+			open = -1
+		}
+	}
+	return sections
+}
+
+// replaceSection replaces the content between the markers of the section
+func (d *Doc) replaceSection(s section, content string) {
+	d.apply(d.sectionEdit(s, content))
+}
+
+// sectionEdit returns the edit replacing the content between the markers of the section.
+// If both markers are on the same line, the content is inserted between them: <!--name-->content<!--/name-->
+// Otherwise the content is put on the lines between the markers, prefixed like the start marker if it is
+// nested in a blockquote or list item.
+func (d *Doc) sectionEdit(s section, content string) edit {
+	content = strings.TrimSpace(content)
+	prefix := containerPrefix(string(d.source[lineStart(d.source, s.start.start):s.start.start]))
+	startLineEnd := lineEnd(d.source, s.start.end)
+	switch {
+	case !bytes.Contains(d.source[s.start.end:s.end.start], []byte("\n")):
+		return edit{s.start.end, s.end.start, content}
+	default:
+		endPrefix := string(d.source[lineStart(d.source, s.end.start):s.end.start])
+		if strings.Trim(endPrefix, " \t>") != "" {
+			endPrefix = prefix
+		}
+		return edit{startLineEnd, s.end.start, fmt.Sprintf("\n%s\n%s", prefixLines(content, prefix), endPrefix)}
+	}
+}
+
+// content returns the source between the markers of a closed section
+func (d *Doc) content(s section) string {
+	return string(d.source[s.start.end:s.end.start])
+}
+
+// splice replaces the source between start and end with replacement
+func (d *Doc) splice(start int, end int, replacement string) {
+	d.apply(edit{start, end, replacement})
+}
+
+// apply applies the edits in a single pass over the source.
+// The edits have to be ordered by their offsets and must not overlap.
+func (d *Doc) apply(edits ...edit) {
+	size := len(d.source)
+	for _, e := range edits {
+		size += len(e.replacement) - (e.end - e.start)
+	}
+	source := make([]byte, 0, size)
+	offset := 0
+	for _, e := range edits {
+		source = append(source, d.source[offset:e.start]...)
+		source = append(source, e.replacement...)
+		offset = e.end
+	}
+	d.source = append(source, d.source[offset:]...)
+	if d.sections != nil && !d.sections.shift(edits) {
+		d.sections = nil
+	}
+}
+
+// shift moves the markers and fences after the edits to their new offsets and drops the ones that were replaced.
+// It returns false if the index can't be kept up to date, because an edit could change how the rest of
+// the document is parsed.
+func (index *sectionIndex) shift(edits []edit) bool {
+	for _, e := range edits {
+		if changesStructure(e.replacement) {
+			return false
+		}
+	}
+	markerOffsets := &offsetShifter{edits: edits}
+	markers := index.markers[:0]
+	for _, m := range index.markers {
+		start, ok := markerOffsets.shift(m.start, m.end)
+		if !ok {
+			continue
+		}
+		m.end, m.start = start+m.end-m.start, start
+		markers = append(markers, m)
+	}
+	index.markers = markers
+	fenceOffsets := &offsetShifter{edits: edits}
+	fences := index.fences[:0]
+	for _, f := range index.fences {
+		start, ok := fenceOffsets.shift(f.start, f.start+len(f.content))
+		if !ok {
+			continue
+		}
+		f.start = start
+		fences = append(fences, f)
+	}
+	index.fences = fences
+	return true
+}
+
+// offsetShifter maps offsets before the edits to offsets after the edits.
+// The ranges have to be passed in ascending order, so all ranges are shifted in a single pass over the edits.
+type offsetShifter struct {
+	edits []edit
+	next  int
+	delta int
+}
+
+// shift returns the new offset of the range between start and end, or false if the range was replaced by an edit
+func (s *offsetShifter) shift(start int, end int) (int, bool) {
+	for s.next < len(s.edits) && s.edits[s.next].end <= start {
+		e := s.edits[s.next]
+		s.delta += len(e.replacement) - (e.end - e.start)
+		s.next++
+	}
+	if s.next < len(s.edits) && s.edits[s.next].start < end {
+		return 0, false
+	}
+	return start + s.delta, true
+}
+
+// changesStructure reports whether inserting the text could change how the rest of the document is parsed,
+// e.g. by opening a code block or an HTML comment, or by escaping the next marker.
+func changesStructure(text string) bool {
+	// Complete comments (e.g. the generated comment) can't change the structure, unless they are markers
+	text = commentPattern.ReplaceAllStringFunc(text, func(comment string) string {
+		if _, ok := parseMarker(comment); ok {
+			return comment
+		}
+		return ""
+	})
+	return strings.ContainsAny(text, "<\\") ||
+		strings.Contains(text, "```") ||
+		strings.Contains(text, "~~~") ||
+		// A code span inserted on the line of a marker could continue up to a backtick after the marker
+		(!strings.Contains(text, "\n") && strings.Contains(text, "`"))
+}
+
+// lineStart returns the offset of the first byte of the line containing offset
+func lineStart(source []byte, offset int) int {
+	return bytes.LastIndexByte(source[:offset], '\n') + 1
+}
+
+// lineEnd returns the offset of the line break ending the line containing offset, or the length of source
+func lineEnd(source []byte, offset int) int {
+	i := bytes.IndexByte(source[offset:], '\n')
+	if i == -1 {
+		return len(source)
+	}
+	return offset + i
+}
+
+// lineNumber returns the 1-based line number of offset
+func lineNumber(source []byte, offset int) int {
+	return bytes.Count(source[:offset], []byte("\n")) + 1
+}
+
+// containerPrefix returns the prefix for continuation lines of the blockquotes and list items a marker is nested in.
+// List markers are replaced by spaces and everything after the containers is dropped.
+// Example: "> - " -> ">   "
+func containerPrefix(linePrefix string) string {
+	var sb strings.Builder
+	for i := 0; i < len(linePrefix); i++ {
+		c := linePrefix[i]
+		switch {
+		case c == '>' || c == ' ' || c == '\t':
+			sb.WriteByte(c)
+		case c == '-' || c == '*' || c == '+':
+			sb.WriteByte(' ')
+		case c >= '0' && c <= '9':
+			j := i
+			for j < len(linePrefix) && linePrefix[j] >= '0' && linePrefix[j] <= '9' {
+				j++
+			}
+			if j == len(linePrefix) || (linePrefix[j] != '.' && linePrefix[j] != ')') {
+				return sb.String()
+			}
+			sb.WriteString(strings.Repeat(" ", j-i+1))
+			i = j
+		default:
+			return sb.String()
+		}
+	}
+	return sb.String()
+}
+
+// prefixLines prefixes all lines of content, empty lines don't get trailing whitespace
+func prefixLines(content string, prefix string) string {
+	if prefix == "" {
+		return content
+	}
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		if line == "" {
+			lines[i] = strings.TrimRight(prefix, " \t")
+		} else {
+			lines[i] = prefix + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,201 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/reakaleek/gh-action-readme/internal/action"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkersIgnoreCode(t *testing.T) {
+	// arrange
+	doc := Doc{
+		source: fromLines(
+			"<!--inputs-->",
+			"```",
+			"<!--outputs-->",
+			"```",
+			"~~~markdown",
+			"<!--outputs-->",
+			"~~~",
+			"",
+			"    <!--outputs-->",
+			"",
+			"Use `<!--outputs-->` to document the outputs.",
+			"<!--",
+			"<!--outputs-->",
+			"-->",
+			"<!--/inputs-->",
+		),
+	}
+
+	// act
+	markers := doc.markers()
+
+	// assert
+	var names []string
+	for _, m := range markers {
+		if m.closing {
+			names = append(names, "/"+m.name)
+		} else {
+			names = append(names, m.name)
+		}
+	}
+	assert.Equal(t, []string{"inputs", "/inputs"}, names)
+}
+
+func TestReplaceSectionInBlockquote(t *testing.T) {
+	// arrange
+	doc := Doc{
+		source: fromLines(
+			"> [!NOTE]",
+			"> <!--description-->",
+			"> old",
+			"> <!--/description-->",
+		),
+	}
+
+	// act
+	updateFirstSection(&doc, descriptionSectionName, "first\n\nsecond")
+
+	// assert
+	expected := fromLines(
+		"> [!NOTE]",
+		"> <!--description-->",
+		"> first",
+		">",
+		"> second",
+		"> <!--/description-->",
+	)
+	assert.Equal(t, string(expected), doc.ToString())
+}
+
+func TestReplaceSectionInListItem(t *testing.T) {
+	// arrange
+	doc := Doc{
+		source: fromLines(
+			"- Inputs:",
+			"  <!--inputs-->",
+			"  <!--/inputs-->",
+			"1. <!--description-->",
+			"2. Done",
+		),
+	}
+
+	// act
+	doc.closeSections()
+	updateFirstSection(&doc, inputsSectionName, "| a |\n|---|")
+	updateFirstSection(&doc, descriptionSectionName, "text")
+
+	// assert
+	expected := fromLines(
+		"- Inputs:",
+		"  <!--inputs-->",
+		"  | a |",
+		"  |---|",
+		"  <!--/inputs-->",
+		"1. <!--description-->",
+		"   text",
+		"   <!--/description-->",
+		"2. Done",
+	)
+	assert.Equal(t, string(expected), doc.ToString())
+}
+
+func TestReplaceSectionPreservesSourceOutsideSections(t *testing.T) {
+	// arrange
+	doc := Doc{
+		source: []byte("Intro  \r\n\t\n<!-- description --> keep\r\nold\r\n  <!--/description--> keep too\r\n~~~\r\n<!--description-->\r\n~~~\r\nend   "),
+	}
+
+	// act
+	updateFirstSection(&doc, descriptionSectionName, "new")
+
+	// assert
+	assert.Equal(t, "Intro  \r\n\t\n<!-- description --> keep\r\nnew\n  <!--/description--> keep too\r\n~~~\r\n<!--description-->\r\n~~~\r\nend   ", doc.ToString())
+}
+
+func TestContainerPrefix(t *testing.T) {
+	tests := []struct {
+		linePrefix string
+		expected   string
+	}{
+		{"", ""},
+		{"  ", "  "},
+		{"> ", "> "},
+		{"> - ", ">   "},
+		{"10. ", "    "},
+		{"* > ", "  > "},
+		{"# ", ""},
+		{"Text ", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.linePrefix, func(t *testing.T) {
+			assert.Equal(t, tt.expected, containerPrefix(tt.linePrefix))
+		})
+	}
+}
+
+func TestIndexIsKeptUpToDate(t *testing.T) {
+	// arrange
+	doc := Doc{source: catalogReadme(3)}
+	doc.index()
+
+	// act
+	doc.ensureGeneratedComment()
+	assert.NoError(t, doc.updateName(&action.Action{Name: "Catalog"}))
+	assert.NoError(t, doc.updateDescription(&action.Action{Description: "A description."}))
+
+	// assert
+	assert.NotNil(t, doc.sections, "the index should be shifted instead of rebuilt")
+	assert.Equal(t, doc.buildIndex(), doc.sections)
+}
+
+func TestIndexIsRebuiltIfEditChangesStructure(t *testing.T) {
+	// arrange
+	doc := Doc{
+		source: fromLines(
+			"<!--description-->",
+			"<!--/description-->",
+			"<!--name--><!--/name-->",
+		),
+	}
+	doc.index()
+
+	// act
+	assert.NoError(t, doc.updateDescription(&action.Action{Description: "```"}))
+
+	// assert
+	assert.Nil(t, doc.sections)
+	assert.Len(t, doc.markers(), 1, "all markers after the unclosed code block are code")
+}
+
+func TestChangesStructure(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected bool
+	}{
+		{"My Action", false},
+		{"\n| `name` | description |\n", false},
+		{"<!-- Generated by https://github.com/reakaleek/gh-action-readme -->\n", false},
+		{"`code", true},
+		{"\n```yaml\n", true},
+		{"\n~~~\n", true},
+		{"<!--", true},
+		{"<!--name-->", true},
+		{"<details>", true},
+		{"trailing \\", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			assert.Equal(t, tt.expected, changesStructure(tt.text))
+		})
+	}
+}
+
+// updateFirstSection replaces the content of the first section with the given name
+func updateFirstSection(d *Doc, name string, content string) {
+	if s, ok := d.firstSection(name); ok {
+		d.replaceSection(s, content)
+	}
+}
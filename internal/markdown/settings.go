@@ -0,0 +1,60 @@
+package markdown
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Settings are the project defaults of a document, e.g. from the configuration file of the repository
+type Settings struct {
+	// Defaults are the attributes of the placeholders by placeholder name.
+	// They are used for the placeholders that don't set the attribute themselves.
+	// Example: {"inputs": {"columns": "name,description"}}
+	Defaults map[string]map[string]string
+	// Env are the values of the environment variables referenced with env: in attributes.
+	// The environment takes precedence, so a workflow can still set another value.
+	Env map[string]string
+	// TemplateDirs are searched for the file of a template placeholder that isn't relative to the document
+	TemplateDirs []string
+}
+
+// IsPlaceholder reports whether name is the name of a placeholder
+func IsPlaceholder(name string) bool {
+	return slices.Contains(placeholders, name)
+}
+
+// Configure applies the settings to the placeholders of the document
+func (d *Doc) Configure(settings Settings) {
+	d.settings = settings
+	// The markers are read again with the default attributes
+	d.sections = nil
+}
+
+// withDefaults adds the default attributes that the start marker doesn't set to its text,
+// so they are read like the attributes of the comment
+// Example: <!--inputs--> -> <!--inputs columns="name,description"-->
+func (s Settings) withDefaults(m marker) marker {
+	defaults := s.Defaults[m.name]
+	if m.closing || len(defaults) == 0 {
+		return m
+	}
+	var attributes strings.Builder
+	for _, name := range slices.Sorted(maps.Keys(defaults)) {
+		if _, ok := getAttributeOk(m.text, name); !ok {
+			fmt.Fprintf(&attributes, ` %s="%s"`, name, defaults[name])
+		}
+	}
+	m.text = strings.TrimSuffix(m.text, "-->") + attributes.String() + "-->"
+	return m
+}
+
+// lookupEnv returns the value of the environment variable, or the value of the settings if it isn't set
+func (s Settings) lookupEnv(name string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return s.Env[name]
+}
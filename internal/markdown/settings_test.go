@@ -0,0 +1,124 @@
+package markdown
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/reakaleek/gh-action-readme/internal/action"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigure_Defaults(t *testing.T) {
+	// arrange
+	doc := Doc{
+		source: fromLines(
+			"<!--inputs-->",
+			"<!--/inputs-->",
+			"<!--inputs columns=\"name,required\"-->",
+			"<!--/inputs-->",
+		),
+	}
+	doc.Configure(Settings{Defaults: map[string]map[string]string{
+		"inputs": {"columns": "name,description", "sort": "alpha"},
+	}})
+	a := &action.Action{
+		Inputs: action.Inputs{
+			"token":   {Description: "The token", Required: true},
+			"dry-run": {Description: "Only print the plan"},
+		},
+		InputsOrder: []string{"token", "dry-run"},
+	}
+
+	// act
+	err := doc.updateInputs(a)
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, string(fromLines(
+		"<!--inputs-->",
+		"| Name      | Description         |",
+		"|-----------|---------------------|",
+		"| `dry-run` | Only print the plan |",
+		"| `token`   | The token           |",
+		"<!--/inputs-->",
+		"<!--inputs columns=\"name,required\"-->",
+		"| Name      | Required |",
+		"|-----------|----------|",
+		"| `dry-run` | `false`  |",
+		"| `token`   | `true`   |",
+		"<!--/inputs-->",
+	)), doc.ToString())
+}
+
+func TestConfigure_Env(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      string
+		expected string
+	}{
+		{name: "settings", expected: "owner/repo@v1.2.3"},
+		{name: "environment takes precedence", env: "v2.0.0", expected: "owner/repo@v2.0.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// arrange
+			t.Setenv("ACTION_VERSION", tt.env)
+			doc := Doc{
+				source: fromLines(
+					"<!--usage action=\"owner/repo\" version=\"env:ACTION_VERSION\"-->",
+					"uses: owner/repo@v1",
+					"<!--/usage-->",
+				),
+			}
+			doc.Configure(Settings{Env: map[string]string{"ACTION_VERSION": "v1.2.3"}})
+
+			// act
+			err := doc.UpdateUsage(nil)
+
+			// assert
+			require.NoError(t, err)
+			assert.Contains(t, doc.ToString(), "uses: "+tt.expected)
+		})
+	}
+}
+
+func TestConfigure_TemplateDirs(t *testing.T) {
+	// arrange
+	root := t.TempDir()
+	writeTemplate(t, root, "name.tmpl", "Action {{ .Name }}")
+	doc := Doc{
+		name: filepath.Join(root, "actions", "deploy", "README.md"),
+		source: fromLines(
+			"<!--template file=\"name.tmpl\"-->",
+			"<!--/template-->",
+		),
+	}
+	doc.Configure(Settings{TemplateDirs: []string{filepath.Join(root, "docs")}})
+
+	// act
+	err := doc.updateTemplates(&action.Action{Name: "Deploy"})
+
+	// assert
+	require.NoError(t, err)
+	assert.Contains(t, doc.ToString(), "Action Deploy")
+}
+
+func TestConfigure_IntegrityReportsTheCommentAsWritten(t *testing.T) {
+	// arrange
+	doc := Doc{
+		source: fromLines(
+			"<!--inputs-->",
+			"<!--inputs-->",
+			"<!--/inputs-->",
+		),
+	}
+	doc.Configure(Settings{Defaults: map[string]map[string]string{"inputs": {"style": "list"}}})
+
+	// act
+	err := doc.checkMarkers()
+
+	// assert
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "<!--inputs--> is opened again on line 2")
+}
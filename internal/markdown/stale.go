@@ -0,0 +1,53 @@
+package markdown
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"github.com/reakaleek/gh-action-readme/internal/action"
+)
+
+// StaleSection is a placeholder section whose content differs from the generated document
+type StaleSection struct {
+	Name string
+	// Line is the line of the start marker, or 1 if the section is missing in the document
+	Line int
+	// Detail describes how the section differs
+	// Example: "2 rows changed (added `token`, default of `retries` changed)"
+	Detail string
+}
+
+func (s StaleSection) String() string {
+	return fmt.Sprintf("%s: %s", s.Name, s.Detail)
+}
+
+// StaleSections compares the placeholder sections of the document with the sections of the given generated document.
+// Sections are paired by name in order of appearance. A section is stale if its content differs, it is not closed,
+// or the document doesn't have it at all.
+// The rows of inputs and outputs tables are compared with the matrix of the given action, or the action
+// referenced by the section.
+func (d *Doc) StaleSections(expected *Doc, a *action.Action) []StaleSection {
+	var stale []StaleSection
+	for _, name := range placeholders {
+		current := d.sectionsNamed(name)
+		for i, s := range expected.sectionsNamed(name) {
+			if i >= len(current) {
+				stale = append(stale, StaleSection{Name: name, Line: 1, Detail: "missing"})
+				continue
+			}
+			c := current[i]
+			if c.end == nil || s.end == nil || d.content(c) != expected.content(s) {
+				stale = append(stale, StaleSection{
+					Name:   name,
+					Line:   lineNumber(d.source, c.start.start),
+					Detail: d.drift(c, expected, s, a),
+				})
+			}
+		}
+	}
+	slices.SortStableFunc(stale, func(a, b StaleSection) int {
+		return cmp.Compare(a.Line, b.Line)
+	})
+	return stale
+}
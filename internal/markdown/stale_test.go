@@ -0,0 +1,58 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStaleSections(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  []string
+		expected []string
+		stale    []StaleSection
+	}{
+		{
+			name:     "up to date",
+			current:  []string{"# <!--name-->Test<!--/name-->", "<!--inputs-->", "table", "<!--/inputs-->"},
+			expected: []string{"# <!--name-->Test<!--/name-->", "<!--inputs-->", "table", "<!--/inputs-->"},
+		},
+		{
+			name:     "changed content",
+			current:  []string{"# <!--name-->Old<!--/name-->", "<!--inputs-->", "old", "<!--/inputs-->"},
+			expected: []string{"# <!--name-->New<!--/name-->", "<!--inputs-->", "new", "<!--/inputs-->"},
+			stale:    []StaleSection{{Name: "name", Line: 1, Detail: `changed from "Old" to "New"`}, {Name: "inputs", Line: 2, Detail: `changed from "old" to "new"`}},
+		},
+		{
+			name:     "unclosed section",
+			current:  []string{"text", "<!--outputs-->"},
+			expected: []string{"text", "<!--outputs-->", "<!--/outputs-->"},
+			stale:    []StaleSection{{Name: "outputs", Line: 2, Detail: "not closed"}},
+		},
+		{
+			name:     "second section of the same name",
+			current:  []string{"<!--inputs action=\"./a\"-->", "a", "<!--/inputs-->", "<!--inputs action=\"./b\"-->", "old", "<!--/inputs-->"},
+			expected: []string{"<!--inputs action=\"./a\"-->", "a", "<!--/inputs-->", "<!--inputs action=\"./b\"-->", "new", "<!--/inputs-->"},
+			stale:    []StaleSection{{Name: "inputs", Line: 4, Detail: `changed from "old" to "new"`}},
+		},
+		{
+			name:     "missing document",
+			expected: []string{"<!--description-->", "text", "<!--/description-->"},
+			stale:    []StaleSection{{Name: "description", Line: 1, Detail: "missing"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// arrange
+			current := &Doc{name: "README.md", source: fromLines(tt.current...)}
+			expected := &Doc{name: "README.md", source: fromLines(tt.expected...)}
+
+			// act
+			stale := current.StaleSections(expected, nil)
+
+			// assert
+			assert.Equal(t, tt.stale, stale)
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package markdown
+
+import (
+	"html"
+	"slices"
+	"strings"
+
+	"github.com/reakaleek/gh-action-readme/internal/action"
+)
+
+// Styles of the inputs and outputs placeholders
+const (
+	styleTable    = "table"
+	styleList     = "list"
+	styleHeadings = "headings"
+	styleHTML     = "html"
+)
+
+var styles = []string{styleTable, styleList, styleHeadings, styleHTML}
+
+// layout renders the matrix of an inputs or outputs table in the configured style.
+// The first row of the matrix are the labels, the columns are in the order of options.columns.
+// level is the heading level of the entries in the headings style.
+func layout(matrix [][]string, options tableOptions, level int) string {
+	switch options.style {
+	case styleList:
+		return list(matrix, options.columns)
+	case styleHeadings:
+		return headings(matrix, options.columns, level)
+	case styleHTML:
+		return htmlTable(matrix)
+	default:
+		return table(matrix)
+	}
+}
+
+// entry is a row of the matrix with the name and description separated from the other columns
+type entry struct {
+	name        string
+	description string
+	// properties are the labels and values of the other columns, without empty values
+	properties [][2]string
+}
+
+func entries(matrix [][]string, columns []string) []entry {
+	nameIndex := slices.Index(columns, action.ColumnName)
+	descriptionIndex := slices.Index(columns, action.ColumnDescription)
+	var result []entry
+	for _, row := range matrix[1:] {
+		e := entry{name: row[nameIndex]}
+		for i, cell := range row {
+			switch {
+			case i == nameIndex:
+			case i == descriptionIndex:
+				e.description = strings.TrimSpace(cell)
+			case strings.TrimSpace(cell) != "":
+				e.properties = append(e.properties, [2]string{matrix[0][i], cell})
+			}
+		}
+		result = append(result, e)
+	}
+	return result
+}
+
+// list renders an item for each row, with the description next to the name and the other columns as nested items
+// Example: "- `token`: The GitHub token." with a nested "- Required: `true`"
+func list(matrix [][]string, columns []string) string {
+	var sb strings.Builder
+	for _, e := range entries(matrix, columns) {
+		sb.WriteString("- " + e.name)
+		if e.description != "" {
+			sb.WriteString(": " + indentContinuation(e.description, "  "))
+		}
+		sb.WriteString("\n")
+		for _, p := range e.properties {
+			sb.WriteString("  - " + p[0] + ": " + indentContinuation(p[1], "    ") + "\n")
+		}
+	}
+	return sb.String()
+}
+
+// headings renders a heading for each row, followed by the full description and the other columns as a list
+func headings(matrix [][]string, columns []string, level int) string {
+	var sb strings.Builder
+	for i, e := range entries(matrix, columns) {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(strings.Repeat("#", level) + " " + e.name + "\n")
+		if e.description != "" {
+			sb.WriteString("\n" + e.description + "\n")
+		}
+		if len(e.properties) > 0 {
+			sb.WriteString("\n")
+		}
+		for _, p := range e.properties {
+			sb.WriteString("- " + p[0] + ": " + indentContinuation(p[1], "  ") + "\n")
+		}
+	}
+	return sb.String()
+}
+
+// htmlTable renders an HTML table. Cells are surrounded by blank lines, so GitHub renders their content as
+// markdown and descriptions can contain paragraphs, lists and code blocks.
+func htmlTable(matrix [][]string) string {
+	var sb strings.Builder
+	sb.WriteString("<table>\n<tr>\n")
+	for _, label := range matrix[0] {
+		sb.WriteString("<th>" + html.EscapeString(label) + "</th>\n")
+	}
+	sb.WriteString("</tr>\n")
+	for _, row := range matrix[1:] {
+		sb.WriteString("<tr>\n")
+		for _, cell := range row {
+			if cell = strings.TrimSpace(cell); cell == "" {
+				sb.WriteString("<td></td>\n")
+				continue
+			}
+			sb.WriteString("<td>\n\n" + cell + "\n\n</td>\n")
+		}
+		sb.WriteString("</tr>\n")
+	}
+	sb.WriteString("</table>\n")
+	return sb.String()
+}
+
+// indentContinuation indents all but the first line, so multi-line text continues a list item
+func indentContinuation(text string, indent string) string {
+	first, rest, ok := strings.Cut(text, "\n")
+	if !ok {
+		return text
+	}
+	return first + "\n" + prefixLines(rest, indent)
+}
@@ -0,0 +1,143 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/reakaleek/gh-action-readme/internal/action"
+	"github.com/stretchr/testify/assert"
+)
+
+// stylesAction has an input with a code block in its description
+var stylesAction = testAction([]string{"token", "debug"}, action.Inputs{
+	"token": {Description: "The GitHub token.\n\nUse a PAT for other repositories:\n```yaml\ntoken: ${{ secrets.PAT }}\n```\n", Required: true},
+	"debug": {Description: "Enable debug logs", Default: "false"},
+})
+
+func TestUpdateInputsStyles(t *testing.T) {
+	tests := []struct {
+		name     string
+		start    string
+		expected []string
+	}{
+		{
+			name:  "list",
+			start: "<!--inputs style=\"list\"-->",
+			expected: []string{
+				"- `token`: The GitHub token.",
+				"",
+				"  Use a PAT for other repositories:",
+				"  ```yaml",
+				"  token: ${{ secrets.PAT }}",
+				"  ```",
+				"  - Required: `true`",
+				"  - Default: ` `",
+				"- `debug`: Enable debug logs",
+				"  - Required: `false`",
+				"  - Default: `false`",
+			},
+		},
+		{
+			name:  "headings",
+			start: "<!--inputs style=\"headings\" columns=\"name,description,default\"-->",
+			expected: []string{
+				"### `token`",
+				"",
+				"The GitHub token.",
+				"",
+				"Use a PAT for other repositories:",
+				"```yaml",
+				"token: ${{ secrets.PAT }}",
+				"```",
+				"",
+				"- Default: ` `",
+				"",
+				"### `debug`",
+				"",
+				"Enable debug logs",
+				"",
+				"- Default: `false`",
+			},
+		},
+		{
+			name:  "html",
+			start: "<!--inputs style=\"html\" columns=\"name,description\" description-label=\"<Description>\"-->",
+			expected: []string{
+				"<table>",
+				"<tr>",
+				"<th>Name</th>",
+				"<th>&lt;Description&gt;</th>",
+				"</tr>",
+				"<tr>",
+				"<td>",
+				"",
+				"`token`",
+				"",
+				"</td>",
+				"<td>",
+				"",
+				"The GitHub token.",
+				"",
+				"Use a PAT for other repositories:",
+				"```yaml",
+				"token: ${{ secrets.PAT }}",
+				"```",
+				"",
+				"</td>",
+				"</tr>",
+				"<tr>",
+				"<td>",
+				"",
+				"`debug`",
+				"",
+				"</td>",
+				"<td>",
+				"",
+				"Enable debug logs",
+				"",
+				"</td>",
+				"</tr>",
+				"</table>",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// arrange
+			doc := Doc{source: fromLines(tt.start, "<!--/inputs-->")}
+
+			// act
+			err := doc.updateInputs(stylesAction)
+
+			// assert
+			assert.NoError(t, err)
+			lines := append([]string{tt.start}, tt.expected...)
+			assert.Equal(t, string(fromLines(append(lines, "<!--/inputs-->")...)), doc.ToString())
+		})
+	}
+}
+
+func TestUpdateInputsHeadingsGrouped(t *testing.T) {
+	// arrange
+	doc := Doc{source: fromLines("<!--inputs style=\"headings\" columns=\"name\" group-by=\"prefix\"-->", "<!--/inputs-->")}
+	a := &action.Action{
+		Inputs:      action.Inputs{"token": {}, "cache-key": {}, "cache-path": {}},
+		InputsOrder: []string{"token", "cache-key", "cache-path"},
+	}
+
+	// act
+	err := doc.updateInputs(a)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, string(fromLines(
+		"<!--inputs style=\"headings\" columns=\"name\" group-by=\"prefix\"-->",
+		"### `token`",
+		"",
+		"### `cache-*`",
+		"",
+		"#### `cache-key`",
+		"",
+		"#### `cache-path`",
+		"<!--/inputs-->",
+	)), doc.ToString())
+}
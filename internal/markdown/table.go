@@ -2,6 +2,8 @@ package markdown
 
 import (
 	"strings"
+
+	"github.com/mattn/go-runewidth"
 )
 
 func table(matrix [][]string) string {
@@ -15,7 +17,7 @@ func table(matrix [][]string) string {
 	}
 	for i := 0; i < len(duplicate); i++ {
 		for j := 0; j < len(duplicate[i]); j++ {
-			duplicate[i][j] = strings.ReplaceAll(duplicate[i][j], "\n", "<br>")
+			duplicate[i][j] = TableCell(duplicate[i][j])
 		}
 	}
 	colWidths := getMaxLengths(duplicate)
@@ -25,9 +27,29 @@ func table(matrix [][]string) string {
 	return sb.String()
 }
 
+// TableCell returns the text as the content of a table cell, line breaks become <br> and pipes are escaped
+func TableCell(text string) string {
+	return escapePipes(strings.ReplaceAll(text, "\n", "<br>"))
+}
+
+// escapePipes escapes the pipes of a cell so they don't split the column.
+// Pipes have to be escaped in code spans too, GitHub removes the backslash there.
+func escapePipes(cell string) string {
+	var sb strings.Builder
+	escaped := false
+	for _, r := range cell {
+		if r == '|' && !escaped {
+			sb.WriteRune('\\')
+		}
+		escaped = r == '\\' && !escaped
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
 func attachHeader(sb *strings.Builder, matrix [][]string, colWidths []int) {
 	for i, col := range matrix[0] {
-		sb.WriteString("| " + col + strings.Repeat(" ", colWidths[i]-len(col)+1)) // +1 for the leading space
+		sb.WriteString("| " + col + strings.Repeat(" ", colWidths[i]-width(col)+1)) // +1 for the leading space
 	}
 	sb.WriteString("|\n")
 	for i := range matrix[0] {
@@ -40,20 +62,31 @@ func attachBody(sb *strings.Builder, matrix [][]string, colWidths []int) {
 	for _, row := range matrix[1:] {
 		sb.WriteString("|")
 		for i, col := range row {
-			sb.WriteString(" " + col + strings.Repeat(" ", colWidths[i]-len(col)) + " |")
+			sb.WriteString(" " + col + strings.Repeat(" ", colWidths[i]-width(col)) + " |")
 		}
 		sb.WriteString("\n")
 	}
 }
 
+// getMaxLengths returns the display width of the widest cell of each column
 func getMaxLengths(data [][]string) []int {
 	maxLengths := make([]int, len(data[0]))
 	for _, row := range data {
 		for i, col := range row {
-			if len(col) > maxLengths[i] {
-				maxLengths[i] = len(col)
+			if w := width(col); w > maxLengths[i] {
+				maxLengths[i] = w
 			}
 		}
 	}
 	return maxLengths
 }
+
+// widthCondition measures ambiguous characters as narrow regardless of the locale,
+// so the generated tables are the same on every machine
+var widthCondition = &runewidth.Condition{StrictEmojiNeutral: true}
+
+// width returns the number of columns the text takes up in a monospace font,
+// e.g. CJK characters and most emoji take up two columns
+func width(text string) int {
+	return widthCondition.StringWidth(text)
+}
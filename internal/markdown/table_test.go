@@ -82,3 +82,17 @@ func TestMultilineString(t *testing.T) {
 		md,
 	)
 }
+
+func TestEscapePipes(t *testing.T) {
+	assert.Equal(t, `a \| b`, escapePipes("a | b"))
+	assert.Equal(t, `a \| b`, escapePipes(`a \| b`))
+	assert.Equal(t, `a \\\| b`, escapePipes(`a \\| b`))
+	assert.Equal(t, "`x \\|\\| y`", escapePipes("`x || y`"))
+}
+
+func TestWidth(t *testing.T) {
+	assert.Equal(t, 5, width("plain"))
+	assert.Equal(t, 6, width("日本語"))
+	assert.Equal(t, 2, width("🚀"))
+	assert.Equal(t, 1, width("é"))
+}
@@ -0,0 +1,131 @@
+package markdown
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/reakaleek/gh-action-readme/internal/action"
+)
+
+// markdownSpecialCharacters are escaped by the escape template function
+const markdownSpecialCharacters = "\\`*_[]<>|"
+
+// templateFuncs are the functions available in templates in addition to the text/template builtins
+var templateFuncs = template.FuncMap{
+	"escape":  escapeMarkdown,
+	"code":    action.CodeSpan,
+	"slugify": slugify,
+	"join":    join,
+}
+
+// templateData is what templates are executed against.
+// Inputs, outputs and secrets are lists in the order of the action file.
+type templateData struct {
+	Name        string
+	Author      string
+	Description string
+	Inputs      []templateInput
+	Outputs     []templateOutput
+	Secrets     []templateSecret
+	Runs        action.Runs
+	Branding    action.Branding
+}
+
+type templateInput struct {
+	Name string
+	action.Input
+}
+
+type templateOutput struct {
+	Name string
+	action.Output
+}
+
+type templateSecret struct {
+	Name string
+	action.Secret
+}
+
+func newTemplateData(a *action.Action) templateData {
+	data := templateData{
+		Name:        a.Name,
+		Author:      a.Author,
+		Description: a.Description,
+		Runs:        a.Runs,
+		Branding:    a.Branding,
+	}
+	for _, key := range a.InputsOrder {
+		data.Inputs = append(data.Inputs, templateInput{Name: key, Input: a.Inputs[key]})
+	}
+	for _, key := range a.OutputsOrder {
+		data.Outputs = append(data.Outputs, templateOutput{Name: key, Output: a.Outputs[key]})
+	}
+	for _, key := range a.SecretsOrder {
+		data.Secrets = append(data.Secrets, templateSecret{Name: key, Secret: a.Secrets[key]})
+	}
+	return data
+}
+
+// updateTemplates renders every template section with the template file referenced by its file attribute
+// Example: <!--template file="docs/inputs.tmpl"-->
+func (d *Doc) updateTemplates(a *action.Action) error {
+	return d.updateSections(d.sectionsNamed(templateSectionName), a, d.renderTemplate)
+}
+
+func (d *Doc) renderTemplate(s section, a *action.Action) (string, error) {
+	file, err := getAttribute(s.start.text, "file")
+	if err != nil {
+		return "", fmt.Errorf("missing file attribute in template placeholder. add the path of the template relative to the README, e.g. file=\"docs/inputs.tmpl\"")
+	}
+	text, err := d.readTemplate(file)
+	if err != nil {
+		return "", fmt.Errorf("failed to read template: %w", err)
+	}
+	// The template is named after the file, so errors read "template: docs/inputs.tmpl:3: ..."
+	t, err := template.New(file).Funcs(templateFuncs).Parse(string(text))
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	if err := t.Execute(&sb, newTemplateData(a)); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// readTemplate reads the template file relative to the document, or else from the template directories of the settings
+func (d *Doc) readTemplate(file string) ([]byte, error) {
+	text, err := os.ReadFile(filepath.Join(filepath.Dir(d.name), file))
+	if !errors.Is(err, os.ErrNotExist) || filepath.IsAbs(file) {
+		return text, err
+	}
+	for _, dir := range d.settings.TemplateDirs {
+		if text, dirErr := os.ReadFile(filepath.Join(dir, file)); dirErr == nil {
+			return text, nil
+		}
+	}
+	return nil, err
+}
+
+// escapeMarkdown escapes the characters that would otherwise be rendered as markdown or HTML
+// Example: "a|b" -> "a\|b"
+func escapeMarkdown(text string) string {
+	var sb strings.Builder
+	for _, r := range text {
+		if strings.ContainsRune(markdownSpecialCharacters, r) {
+			sb.WriteRune('\\')
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// join concatenates the items with the separator, it takes the list last so it can be used in pipelines
+// Example: {{ .Runs.Args | join ", " }}
+func join(separator string, items []string) string {
+	return strings.Join(items, separator)
+}
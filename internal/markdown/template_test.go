@@ -0,0 +1,121 @@
+package markdown
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/reakaleek/gh-action-readme/internal/action"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTemplate writes a template file to the docs directory of root
+func writeTemplate(t *testing.T, root string, name string, lines ...string) {
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "docs"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "docs", name), fromLines(lines...), 0644))
+}
+
+func TestUpdateTemplates(t *testing.T) {
+	// arrange
+	root := t.TempDir()
+	writeTemplate(t, root, "inputs.tmpl",
+		"{{ range .Inputs }}",
+		"### {{ code .Name }}",
+		"",
+		"{{ escape .Description }}{{ if .Required }} (required){{ end }}",
+		"{{ end }}",
+		"Runs on {{ .Runs.Using }} with {{ .Runs.Args | join \" \" }}, branded {{ .Branding.Color }}.",
+	)
+	doc := Doc{
+		name: filepath.Join(root, "README.md"),
+		source: fromLines(
+			"<!--template file=\"docs/inputs.tmpl\"-->",
+			"<!--/template-->",
+		),
+	}
+	a := &action.Action{
+		Inputs: action.Inputs{
+			"token":   {Description: "The <token> to use", Required: true},
+			"dry-run": {Description: "Only print the *plan*"},
+		},
+		InputsOrder: []string{"token", "dry-run"},
+		Runs:        action.Runs{Using: "docker", Args: []string{"--verbose", "--fast"}},
+		Branding:    action.Branding{Icon: "package", Color: "blue"},
+	}
+
+	// act
+	err := doc.updateTemplates(a)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, string(fromLines(
+		"<!--template file=\"docs/inputs.tmpl\"-->",
+		"### `token`",
+		"",
+		"The \\<token\\> to use (required)",
+		"",
+		"### `dry-run`",
+		"",
+		"Only print the \\*plan\\*",
+		"",
+		"Runs on docker with --verbose --fast, branded blue.",
+		"<!--/template-->",
+	)), doc.ToString())
+}
+
+func TestUpdateTemplatesErrors(t *testing.T) {
+	root := t.TempDir()
+	writeTemplate(t, root, "parse.tmpl", "line 1", "{{ end }}", "line 3")
+	writeTemplate(t, root, "execute.tmpl", "line 1", "", "{{ .Unknown }}")
+	writeTemplate(t, root, "function.tmpl", "{{ unknown .Name }}")
+	tests := []struct {
+		name   string
+		source string
+		err    string
+	}{
+		{
+			name:   "missing file attribute",
+			source: "<!--template-->",
+			err:    "missing file attribute",
+		},
+		{
+			name:   "missing file",
+			source: "<!--template file=\"docs/missing.tmpl\"-->",
+			err:    "failed to read template",
+		},
+		{
+			name:   "parse error",
+			source: "<!--template file=\"docs/parse.tmpl\"-->",
+			err:    "template: docs/parse.tmpl:2: unexpected {{end}}",
+		},
+		{
+			name:   "execution error",
+			source: "<!--template file=\"docs/execute.tmpl\"-->",
+			err:    "template: docs/execute.tmpl:3:3: executing",
+		},
+		{
+			name:   "unknown function",
+			source: "<!--template file=\"docs/function.tmpl\"-->",
+			err:    "template: docs/function.tmpl:1: function \"unknown\" not defined",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// arrange
+			doc := Doc{name: filepath.Join(root, "README.md"), source: []byte(tt.source)}
+
+			// act
+			err := doc.updateTemplates(&action.Action{})
+
+			// assert
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestTemplateFuncs(t *testing.T) {
+	assert.Equal(t, "a\\|b \\`c\\` \\_d\\_ \\[e\\](f) \\\\", escapeMarkdown("a|b `c` _d_ [e](f) \\"))
+	assert.Equal(t, "a, b", join(", ", []string{"a", "b"}))
+	assert.Equal(t, "dry-run", slugify("Dry Run!"))
+}
@@ -1,35 +1,129 @@
 package markdown
 
-import "strings"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
 
-func toc(lines []string, indent int, startDepth int) string {
+	"github.com/yuin/goldmark/ast"
+)
+
+type heading struct {
+	level int
+	text  string
+	slug  string
+}
+
+// headings returns all headings of the document with their GitHub anchor.
+// Headings in code blocks are not part of the parsed document and thus ignored.
+func (d *Doc) headings() []heading {
+	var result []heading
+	slugs := make(map[string]int)
+	_ = ast.Walk(d.parse(), func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		h, ok := n.(*ast.Heading)
+		if !ok || !entering {
+			return ast.WalkContinue, nil
+		}
+		text := strings.TrimSpace(inlineText(h, d.source))
+		result = append(result, heading{
+			level: h.Level,
+			text:  text,
+			slug:  uniqueSlug(slugify(text), slugs),
+		})
+		return ast.WalkSkipChildren, nil
+	})
+	return result
+}
+
+// inlineText returns the text of the inline children of n.
+// Code spans keep their backticks, emphasis markers and link targets are dropped.
+func inlineText(n ast.Node, source []byte) string {
 	var sb strings.Builder
-	for _, line := range lines {
-		if isHeader(line) {
-			headerLevel := getHeaderLevel(line)
-			if headerLevel >= startDepth {
-				numSpaces := (headerLevel - startDepth) * indent
-				ind := strings.Repeat(" ", numSpaces)
-				heading := strings.TrimLeft(line, "# ")
-				sb.WriteString(ind + "- " + heading + "\n")
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		switch c := c.(type) {
+		case *ast.Text:
+			sb.Write(c.Segment.Value(source))
+			if c.SoftLineBreak() {
+				sb.WriteByte(' ')
 			}
+		case *ast.String:
+			sb.Write(c.Value)
+		case *ast.CodeSpan:
+			sb.WriteString("`" + inlineText(c, source) + "`")
+		case *ast.AutoLink:
+			sb.Write(c.Label(source))
+		case *ast.RawHTML:
+			// HTML is not rendered as part of the heading text
+		default:
+			sb.WriteString(inlineText(c, source))
 		}
 	}
-	return strings.TrimSpace(sb.String())
+	return sb.String()
 }
 
-func isHeader(line string) bool {
-	return strings.HasPrefix(line, "#")
+// slugify returns the anchor GitHub generates for a heading:
+// lowercase, punctuation removed and spaces replaced by hyphens
+func slugify(text string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case r == ' ':
+			sb.WriteRune('-')
+		case r == '-' || r == '_':
+			sb.WriteRune(r)
+		case unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.IsMark(r):
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
 }
 
-func getHeaderLevel(line string) int {
-	level := 0
-	for _, r := range line {
-		if r == '#' {
-			level++
-		} else {
+// uniqueSlug appends -1, -2, ... to slugs that were already used, like GitHub does for duplicate headings
+func uniqueSlug(slug string, slugs map[string]int) string {
+	unique := slug
+	for {
+		count, ok := slugs[unique]
+		if !ok {
 			break
 		}
+		slugs[unique] = count + 1
+		unique = fmt.Sprintf("%s-%d", slug, count+1)
+	}
+	slugs[unique] = 0
+	return unique
+}
+
+// toc renders a nested list of links to the headings with a level between minDepth and maxDepth
+func toc(headings []heading, indent int, minDepth int, maxDepth int) string {
+	var sb strings.Builder
+	for _, h := range headings {
+		if h.level < minDepth || h.level > maxDepth {
+			continue
+		}
+		ind := strings.Repeat(" ", (h.level-minDepth)*indent)
+		sb.WriteString(fmt.Sprintf("%s- [%s](#%s)\n", ind, h.text, h.slug))
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// parseDepth parses the depth attribute of the toc placeholder
+// Example: "2-4" -> 2, 4 and "3" -> 3, 3
+func parseDepth(depth string) (int, int, error) {
+	bounds := strings.SplitN(depth, "-", 2)
+	minDepth, err := strconv.Atoi(strings.TrimSpace(bounds[0]))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid toc depth %q, expected <min>-<max> (e.g. 2-4)", depth)
+	}
+	maxDepth := minDepth
+	if len(bounds) == 2 {
+		maxDepth, err = strconv.Atoi(strings.TrimSpace(bounds[1]))
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid toc depth %q, expected <min>-<max> (e.g. 2-4)", depth)
+		}
+	}
+	if minDepth < 1 || maxDepth > 6 || minDepth > maxDepth {
+		return 0, 0, fmt.Errorf("invalid toc depth %q, expected levels between 1 and 6", depth)
 	}
-	return level
+	return minDepth, maxDepth, nil
 }
@@ -16,13 +16,13 @@ func TestToc(t *testing.T) {
 	}
 
 	// act
-	result := toc(lines, 2, 2)
+	result := toc(docHeadings(lines...), 2, 2, 6)
 
 	// assert
 	expected := strings.Join([]string{
-		"- Bar",
-		"  - Baz",
-		"- Qux",
+		"- [Bar](#bar)",
+		"  - [Baz](#baz)",
+		"- [Qux](#qux)",
 	}, "\n")
 	assert.Equal(t, expected, result)
 }
@@ -37,14 +37,126 @@ func TestToc2(t *testing.T) {
 	}
 
 	// act
-	result := toc(lines, 3, 1)
+	result := toc(docHeadings(lines...), 3, 1, 2)
 
 	// assert
 	expected := strings.Join([]string{
-		"- Foo",
-		"   - Bar",
-		"      - Baz",
-		"   - Qux",
+		"- [Foo](#foo)",
+		"   - [Bar](#bar)",
+		"   - [Qux](#qux)",
 	}, "\n")
 	assert.Equal(t, expected, result)
 }
+
+func TestTocDuplicateHeadings(t *testing.T) {
+	// arrange
+	lines := []string{
+		"## Example",
+		"## Example",
+		"## Example-1",
+		"## Example",
+	}
+
+	// act
+	result := toc(docHeadings(lines...), 2, 2, 6)
+
+	// assert
+	expected := strings.Join([]string{
+		"- [Example](#example)",
+		"- [Example](#example-1)",
+		"- [Example-1](#example-1-1)",
+		"- [Example](#example-2)",
+	}, "\n")
+	assert.Equal(t, expected, result)
+}
+
+func TestTocSkipsCodeBlocks(t *testing.T) {
+	// arrange
+	lines := []string{
+		"## Bar",
+		"```bash",
+		"# comment",
+		"```",
+		"~~~",
+		"# comment",
+		"~~~",
+		"",
+		"    # indented",
+		"",
+		"#hashtag",
+	}
+
+	// act
+	result := toc(docHeadings(lines...), 2, 1, 6)
+
+	// assert
+	assert.Equal(t, "- [Bar](#bar)", result)
+}
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"Inputs", "inputs"},
+		{"Basic Usage", "basic-usage"},
+		{"What's new?", "whats-new"},
+		{"`with` inputs", "with-inputs"},
+		{"snake_case & kebab-case", "snake_case--kebab-case"},
+		{"**Bold** move", "bold-move"},
+		{"Ünïcödé", "ünïcödé"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			assert.Equal(t, tt.expected, slugify(tt.text))
+		})
+	}
+}
+
+func TestHeadingText(t *testing.T) {
+	// arrange
+	lines := []string{
+		"# <!--name-->My Action<!--/name--> ##",
+		"## [Docs](https://example.com) ![logo](logo.png)",
+	}
+
+	// act
+	result := docHeadings(lines...)
+
+	// assert
+	assert.Equal(t, []heading{
+		{level: 1, text: "My Action", slug: "my-action"},
+		{level: 2, text: "Docs logo", slug: "docs-logo"},
+	}, result)
+}
+
+func TestParseDepth(t *testing.T) {
+	tests := []struct {
+		depth    string
+		min, max int
+		wantErr  bool
+	}{
+		{"2-4", 2, 4, false},
+		{"3", 3, 3, false},
+		{"4-2", 0, 0, true},
+		{"1-7", 0, 0, true},
+		{"all", 0, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.depth, func(t *testing.T) {
+			minDepth, maxDepth, err := parseDepth(tt.depth)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.min, minDepth)
+			assert.Equal(t, tt.max, maxDepth)
+		})
+	}
+}
+
+func docHeadings(lines ...string) []heading {
+	doc := Doc{source: fromLines(lines...)}
+	return doc.headings()
+}
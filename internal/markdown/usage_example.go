@@ -0,0 +1,76 @@
+package markdown
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/reakaleek/gh-action-readme/internal/action"
+)
+
+const (
+	usageExampleInputsRequired = "required"
+	usageExampleInputsAll      = "all"
+)
+
+// updateUsageExamples generates a workflow step for every usage-example section.
+// Each section is generated independently based on its own attributes.
+func (d *Doc) updateUsageExamples(a *action.Action) error {
+	sections := d.sectionsNamed(usageExampleSectionName)
+	edits := make([]edit, 0, len(sections))
+	for _, s := range sections {
+		if s.end == nil {
+			return fmt.Errorf("missing end comment for usage-example section. add <!--/usage-example--> to the end of the usage-example section")
+		}
+		if !strings.Contains(d.content(s), "\n") {
+			return fmt.Errorf("usage-example section must start and end on separate lines")
+		}
+		actionName, version, err := d.getUsageAttributes(s.start.text)
+		if err != nil {
+			return err
+		}
+		inputs := usageExampleInputsRequired
+		if value, err := getAttribute(s.start.text, "inputs"); err == nil {
+			inputs = value
+		}
+		if inputs != usageExampleInputsRequired && inputs != usageExampleInputsAll {
+			return fmt.Errorf("invalid usage-example inputs %q, expected %q or %q", inputs, usageExampleInputsRequired, usageExampleInputsAll)
+		}
+		target, err := d.actionFor(s, a)
+		if err != nil {
+			return err
+		}
+		edits = append(edits, d.sectionEdit(s, usageExample(target, actionName, version, inputs == usageExampleInputsAll)))
+	}
+	d.apply(edits...)
+	return nil
+}
+
+// usageExample renders a workflow step that uses the action.
+// Required inputs are listed in the with block, optional inputs are added as comments if all is set.
+// Deprecated inputs are omitted.
+func usageExample(a *action.Action, actionName string, version string, all bool) string {
+	var with []string
+	for _, key := range a.InputsOrder {
+		input := a.Inputs[key]
+		if input.IsDeprecated() {
+			continue
+		}
+		switch {
+		case input.Required:
+			with = append(with, fmt.Sprintf("    %s: %s", key, strconv.Quote(input.Default)))
+		case all:
+			with = append(with, fmt.Sprintf("    # %s: %s", key, strconv.Quote(input.Default)))
+		}
+	}
+	lines := []string{
+		"```yaml",
+		fmt.Sprintf("- uses: %s@%s", actionName, version),
+	}
+	if len(with) > 0 {
+		lines = append(lines, "  with:")
+		lines = append(lines, with...)
+	}
+	lines = append(lines, "```")
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,146 @@
+package markdown
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/reakaleek/gh-action-readme/internal/action"
+	"github.com/stretchr/testify/assert"
+)
+
+var usageExampleAction = testAction([]string{"token", "path", "dry-run", "old"}, action.Inputs{
+	"token":   {Description: "The token.", Required: true},
+	"path":    {Description: "The path.", Default: "."},
+	"dry-run": {Description: "Dry run.", Required: true, Default: "false"},
+	"old":     {Description: "Old.", DeprecationMessage: "Use path."},
+})
+
+func TestUpdateUsageExamplesRequired(t *testing.T) {
+	// arrange
+	doc := Doc{
+		source: fromLines(
+			"<!--usage-example action=\"owner/repo\" version=\"v1\"-->",
+			"outdated",
+			"<!--/usage-example-->",
+		),
+	}
+
+	// act
+	err := doc.updateUsageExamples(usageExampleAction)
+
+	// assert
+	assert.NoError(t, err)
+	expected := strings.Join([]string{
+		"<!--usage-example action=\"owner/repo\" version=\"v1\"-->",
+		"```yaml",
+		"- uses: owner/repo@v1",
+		"  with:",
+		"    token: \"\"",
+		"    dry-run: \"false\"",
+		"```",
+		"<!--/usage-example-->",
+	}, "\n")
+	assert.Equal(t, expected, doc.ToString())
+}
+
+func TestUpdateUsageExamplesAll(t *testing.T) {
+	// arrange
+	assert.NoError(t, os.Setenv("USAGE_EXAMPLE_VERSION", "v2.1.0"))
+	defer os.Unsetenv("USAGE_EXAMPLE_VERSION")
+	doc := Doc{
+		source: fromLines(
+			"<!--usage-example action=\"owner/repo\" version=\"env:USAGE_EXAMPLE_VERSION\" inputs=\"all\"-->",
+			"<!--/usage-example-->",
+			"<!--usage-example action=\"owner/repo/sub\" version=\"main\"-->",
+			"<!--/usage-example-->",
+		),
+	}
+
+	// act
+	err := doc.updateUsageExamples(usageExampleAction)
+
+	// assert
+	assert.NoError(t, err)
+	expected := strings.Join([]string{
+		"<!--usage-example action=\"owner/repo\" version=\"env:USAGE_EXAMPLE_VERSION\" inputs=\"all\"-->",
+		"```yaml",
+		"- uses: owner/repo@v2.1.0",
+		"  with:",
+		"    token: \"\"",
+		"    # path: \".\"",
+		"    dry-run: \"false\"",
+		"```",
+		"<!--/usage-example-->",
+		"<!--usage-example action=\"owner/repo/sub\" version=\"main\"-->",
+		"```yaml",
+		"- uses: owner/repo/sub@main",
+		"  with:",
+		"    token: \"\"",
+		"    dry-run: \"false\"",
+		"```",
+		"<!--/usage-example-->",
+	}, "\n")
+	assert.Equal(t, expected, doc.ToString())
+}
+
+func TestUpdateUsageExamplesWithoutInputs(t *testing.T) {
+	// arrange
+	doc := Doc{
+		source: fromLines(
+			"<!--usage-example action=\"owner/repo\" version=\"v1\"-->",
+			"<!--/usage-example-->",
+		),
+	}
+	a := action.New("Test", "Author", "", action.Inputs{}, nil, action.Outputs{}, nil)
+
+	// act
+	err := doc.updateUsageExamples(a)
+
+	// assert
+	assert.NoError(t, err)
+	expected := strings.Join([]string{
+		"<!--usage-example action=\"owner/repo\" version=\"v1\"-->",
+		"```yaml",
+		"- uses: owner/repo@v1",
+		"```",
+		"<!--/usage-example-->",
+	}, "\n")
+	assert.Equal(t, expected, doc.ToString())
+}
+
+func TestUpdateUsageExamplesErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		err   string
+	}{
+		{
+			name:  "missing end comment",
+			lines: []string{"<!--usage-example action=\"owner/repo\" version=\"v1\"-->"},
+			err:   "missing end comment for usage-example section",
+		},
+		{
+			name:  "missing version",
+			lines: []string{"<!--usage-example action=\"owner/repo\"-->", "<!--/usage-example-->"},
+			err:   "failed to get attribute version",
+		},
+		{
+			name:  "unset environment variable",
+			lines: []string{"<!--usage-example action=\"owner/repo\" version=\"env:USAGE_EXAMPLE_UNSET\"-->", "<!--/usage-example-->"},
+			err:   "the environment variable USAGE_EXAMPLE_UNSET is not set",
+		},
+		{
+			name:  "invalid inputs",
+			lines: []string{"<!--usage-example action=\"owner/repo\" version=\"v1\" inputs=\"some\"-->", "<!--/usage-example-->"},
+			err:   "invalid usage-example inputs \"some\"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := Doc{source: fromLines(tt.lines...)}
+			err := doc.updateUsageExamples(usageExampleAction)
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package markdown
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/reakaleek/gh-action-readme/internal/action"
+	"gopkg.in/yaml.v3"
+)
+
+// UsageIssue is a problem with a hand-written usage example
+type UsageIssue struct {
+	File string
+	Line int
+	// Column is the column of the key of the issue, 0 for issues of the whole step (e.g. missing inputs)
+	Column  int
+	Message string
+}
+
+func (i UsageIssue) String() string {
+	if i.Column == 0 {
+		return fmt.Sprintf("%s:%d: %s", i.File, i.Line, i.Message)
+	}
+	return fmt.Sprintf("%s:%d:%d: %s", i.File, i.Line, i.Column, i.Message)
+}
+
+// LintUsage checks the fenced YAML snippets in all usage sections.
+// The with block of every step whose uses matches the action attribute of the section is checked against
+// the inputs of the action, or of the local action the action attribute points to.
+// Line and column numbers refer to the document.
+func (d *Doc) LintUsage(a *action.Action) []UsageIssue {
+	var issues []UsageIssue
+	for _, s := range d.sectionsNamed(usageSectionName) {
+		if s.end == nil {
+			continue
+		}
+		actionGlob, err := getAttribute(s.start.text, "action")
+		if err != nil {
+			continue
+		}
+		if isLocalAction(actionGlob) {
+			if actionGlob, err = getAttribute(s.start.text, "uses"); err != nil {
+				continue
+			}
+		}
+		target, err := d.actionFor(s, a)
+		if err != nil {
+			continue
+		}
+		for _, f := range d.fencesBetween(s.start.end, s.end.start) {
+			lang := strings.ToLower(f.lang)
+			if lang == "" || lang == "yaml" || lang == "yml" {
+				issues = append(issues, d.lintUsageSnippet(target, actionGlob, f)...)
+			}
+		}
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Line < issues[j].Line
+	})
+	return issues
+}
+
+// lintUsageSnippet checks the content of a fenced code block
+func (d *Doc) lintUsageSnippet(a *action.Action, actionGlob string, f fence) []UsageIssue {
+	// The snippet starts on the line after the opening fence
+	fenceLine := lineNumber(d.source, f.start) - 1
+	var root yaml.Node
+	if err := yaml.Unmarshal([]byte(f.content), &root); err != nil {
+		return []UsageIssue{{File: d.name, Line: fenceLine, Message: fmt.Sprintf("usage example is not valid YAML: %v", err)}}
+	}
+	var issues []UsageIssue
+	walkUses(&root, actionGlob, func(uses *yaml.Node, with *yaml.Node) {
+		for _, issue := range a.LintWith(with) {
+			if issue.Line == 0 {
+				issues = append(issues, UsageIssue{File: d.name, Line: fenceLine + uses.Line, Message: issue.Message})
+				continue
+			}
+			issues = append(issues, UsageIssue{
+				File:    d.name,
+				Line:    fenceLine + issue.Line,
+				Column:  d.fenceIndent(f, issue.Line) + issue.Column,
+				Message: issue.Message,
+			})
+		}
+	})
+	return issues
+}
+
+// fenceIndent returns the number of characters in front of the n-th line of the fence content in the document,
+// e.g. the indentation of a code block in a list item
+func (d *Doc) fenceIndent(f fence, n int) int {
+	lines := strings.Split(f.content, "\n")
+	if n > len(lines) {
+		return 0
+	}
+	start := f.start
+	for i := 1; i < n; i++ {
+		start = lineEnd(d.source, start) + 1
+	}
+	line := string(d.source[lineStart(d.source, start):lineEnd(d.source, start)])
+	return utf8.RuneCountInString(line) - utf8.RuneCountInString(lines[n-1])
+}
+
+// walkUses calls fn for every mapping with a uses key that matches the glob
+func walkUses(node *yaml.Node, glob string, fn func(uses *yaml.Node, with *yaml.Node)) {
+	if node.Kind == yaml.MappingNode {
+		var uses, with *yaml.Node
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			switch node.Content[i].Value {
+			case "uses":
+				uses = node.Content[i+1]
+			case "with":
+				with = node.Content[i+1]
+			}
+		}
+		if uses != nil && uses.Kind == yaml.ScalarNode {
+			name, _, _ := strings.Cut(uses.Value, "@")
+			if ok, _ := doublestar.Match(glob, name); ok {
+				fn(uses, with)
+			}
+		}
+	}
+	for _, child := range node.Content {
+		walkUses(child, glob, fn)
+	}
+}
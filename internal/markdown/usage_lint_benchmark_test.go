@@ -0,0 +1,20 @@
+package markdown
+
+import (
+	"fmt"
+	"testing"
+)
+
+func BenchmarkLintUsage(b *testing.B) {
+	a := benchmarkAction()
+	for _, entries := range []int{100, 1000} {
+		source := catalogReadme(entries)
+		b.Run(fmt.Sprintf("entries=%d", entries), func(b *testing.B) {
+			b.SetBytes(int64(len(source)))
+			for i := 0; i < b.N; i++ {
+				doc := Doc{source: source}
+				doc.LintUsage(a)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/reakaleek/gh-action-readme/internal/action"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLintUsage(t *testing.T) {
+	// arrange
+	doc := Doc{
+		name: "README.md",
+		source: fromLines(
+			"# Usage",
+			"<!--usage action=\"owner/repo\" version=\"v1\"-->",
+			"```yaml",
+			"steps:",
+			"  - uses: actions/checkout@v4",
+			"    with:",
+			"      fetch-depth: 0",
+			"  - uses: owner/repo@v1",
+			"    with:",
+			"      tokn: ${{ secrets.GITHUB_TOKEN }}",
+			"      old: true",
+			"```",
+			"```bash",
+			"echo 'not yaml: ['",
+			"```",
+			"<!--/usage-->",
+		),
+	}
+	a := action.New(
+		"Test",
+		"Author",
+		"",
+		action.Inputs{
+			"token": action.Input{Description: "The token.", Required: true},
+			"old":   action.Input{Description: "Old.", DeprecationMessage: "Use token."},
+		},
+		[]string{"token", "old"},
+		action.Outputs{},
+		nil,
+	)
+
+	// act
+	issues := doc.LintUsage(a)
+
+	// assert
+	var messages []string
+	for _, issue := range issues {
+		messages = append(messages, issue.String())
+	}
+	assert.Equal(t, []string{
+		`README.md:8: required input "token" is missing`,
+		`README.md:10:7: input "tokn" is not declared, did you mean "token"?`,
+		`README.md:11:7: input "old" is deprecated: Use token.`,
+	}, messages)
+}
+
+func TestLintUsageInvalidYAML(t *testing.T) {
+	// arrange
+	doc := Doc{
+		name: "README.md",
+		source: fromLines(
+			"<!--usage action=\"owner/repo\" version=\"v1\"-->",
+			"```yml",
+			"- uses: owner/repo@v1",
+			"  with: [",
+			"```",
+			"<!--/usage-->",
+		),
+	}
+	a := action.New("Test", "Author", "", action.Inputs{}, nil, action.Outputs{}, nil)
+
+	// act
+	issues := doc.LintUsage(a)
+
+	// assert
+	assert.Len(t, issues, 1)
+	assert.Equal(t, 2, issues[0].Line)
+	assert.Contains(t, issues[0].Message, "usage example is not valid YAML")
+}
+
+func TestLintUsageNoIssues(t *testing.T) {
+	// arrange
+	doc := Doc{
+		name: "README.md",
+		source: fromLines(
+			"<!--usage action=\"owner/*\" version=\"v1\"-->",
+			"```yaml",
+			"- uses: owner/repo@v1",
+			"  with:",
+			"    token: abc",
+			"```",
+			"<!--/usage-->",
+			"```yaml",
+			"- uses: owner/repo@v1",
+			"```",
+		),
+	}
+	a := action.New(
+		"Test",
+		"Author",
+		"",
+		action.Inputs{"token": action.Input{Description: "The token.", Required: true}},
+		[]string{"token"},
+		action.Outputs{},
+		nil,
+	)
+
+	// act
+	issues := doc.LintUsage(a)
+
+	// assert
+	assert.Empty(t, issues)
+}
+
+func TestLintUsageInListItem(t *testing.T) {
+	// arrange
+	doc := Doc{
+		name: "README.md",
+		source: fromLines(
+			"<!--usage action=\"owner/repo\" version=\"v1\"-->",
+			"1. Add the step:",
+			"   ```yaml",
+			"   - uses: owner/repo@v1",
+			"     with:",
+			"       tokn: abc",
+			"   ```",
+			"<!--/usage-->",
+		),
+	}
+	a := testAction([]string{"token"}, action.Inputs{"token": {Description: "The token."}})
+
+	// act
+	issues := doc.LintUsage(a)
+
+	// assert
+	assert.Equal(t, []UsageIssue{
+		{File: "README.md", Line: 6, Column: 8, Message: `input "tokn" is not declared, did you mean "token"?`},
+	}, issues)
+}
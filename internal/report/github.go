@@ -0,0 +1,122 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/reakaleek/gh-action-readme/internal/action"
+	"github.com/reakaleek/gh-action-readme/internal/markdown"
+)
+
+var (
+	// workflowCommandData escapes the message of a workflow command
+	workflowCommandData = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+	// workflowCommandProperty escapes a property value of a workflow command
+	workflowCommandProperty = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
+)
+
+// WriteGitHub writes an error workflow command for every README that is out-of-date or failed, so GitHub shows
+// an annotation on the first line that differs. The patch of each README is written in a collapsed group of the log.
+// Usage issues are written as warnings on their line and column.
+// Example: ::error file=deploy/README.md,line=12,title=README out-of-date::deploy/README.md is out-of-date ...
+func (r *Report) WriteGitHub(w io.Writer) error {
+	for _, e := range r.Actions {
+		for _, issue := range e.UsageIssues {
+			position := fmt.Sprintf("file=%s,line=%d", workflowCommandProperty.Replace(filepath.ToSlash(issue.File)), issue.Line)
+			if issue.Column > 0 {
+				position += fmt.Sprintf(",col=%d", issue.Column)
+			}
+			if _, err := fmt.Fprintf(w, "::warning %s,title=Usage warning::%s\n", position, workflowCommandData.Replace(issue.Message)); err != nil {
+				return err
+			}
+		}
+		readme := workflowCommandProperty.Replace(filepath.ToSlash(e.Readme))
+		var err error
+		switch e.Status {
+		case StatusOutOfDate:
+			message := fmt.Sprintf("%s is out-of-date, run gh action-readme update", e.Readme)
+			for _, section := range e.StaleSections {
+				message += fmt.Sprintf("\n- %s", section)
+			}
+			_, err = fmt.Fprintf(w, "::error file=%s,line=%d,title=README out-of-date::%s\n::group::%s\n%s::endgroup::\n",
+				readme, max(e.Line, 1), workflowCommandData.Replace(message), workflowCommandData.Replace(e.Readme), e.Patch)
+		case StatusError:
+			_, err = fmt.Fprintf(w, "::error file=%s,title=README error::%s\n", readme, workflowCommandData.Replace(e.Error))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AppendStepSummary appends a Markdown table with the status and the changed sections of every README
+// to the job summary file, which GitHub shows on the summary page of the workflow run.
+func (r *Report) AppendStepSummary(path string) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "### gh action-readme %s\n\n", r.Command)
+	b.WriteString("| Action | README | Status | Changed sections |\n")
+	b.WriteString("|--------|--------|--------|------------------|\n")
+	for _, e := range r.Actions {
+		var changed []string
+		for _, section := range e.StaleSections {
+			changed = append(changed, fmt.Sprintf("%s: %s", action.CodeSpan(section.Name), section.Detail))
+		}
+		details := strings.Join(changed, "\n")
+		if e.Status == StatusError {
+			details = strings.TrimSpace(e.Error)
+		}
+		details = markdown.TableCell(details)
+		actionPath := ""
+		if e.Action != "" {
+			actionPath = action.CodeSpan(filepath.ToSlash(e.Action))
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", actionPath, action.CodeSpan(filepath.ToSlash(e.Readme)), e.Status, details)
+	}
+	fmt.Fprintf(&b, "\n%s\n\n", r.Summary)
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(b.String()); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// String returns the name and the detail of the section
+// Example: "inputs: 1 row changed (added `token`)"
+func (s Section) String() string {
+	return fmt.Sprintf("%s: %s", s.Name, s.Detail)
+}
+
+// String returns the counts that aren't zero
+// Example: "2 out-of-date, 5 up-to-date"
+func (s Summary) String() string {
+	counts := []struct {
+		count int
+		label Status
+	}{
+		{s.OutOfDate, StatusOutOfDate},
+		{s.Error, StatusError},
+		{s.Updated, StatusUpdated},
+		{s.Created, StatusCreated},
+		{s.Skipped, StatusSkipped},
+		{s.UpToDate, StatusUpToDate},
+	}
+	var parts []string
+	for _, c := range counts {
+		if c.count > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", c.count, c.label))
+		}
+	}
+	if len(parts) == 0 {
+		return "No READMEs found"
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,98 @@
+package report
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/reakaleek/gh-action-readme/internal/markdown"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func githubReport() *Report {
+	r := New("diff")
+	r.Add(Entry{Action: "build/action.yml", Readme: "build/README.md", Status: StatusUpToDate})
+	r.Add(Entry{
+		Action:        "deploy/action.yml",
+		Readme:        "deploy/README.md",
+		Status:        StatusOutOfDate,
+		Line:          12,
+		StaleSections: []Section{{Name: "inputs", Line: 10, Detail: "1 row changed (added `a|b`)"}, {Name: "usage", Line: 30, Detail: "content changed (1 line added)"}},
+		Patch:         "--- a/deploy/README.md\n+++ b/deploy/README.md\n@@ -12 +12 @@\n-old\n+new\n",
+		UsageIssues: []markdown.UsageIssue{
+			{File: "deploy/README.md", Line: 31, Column: 7, Message: `input "tokn" is not declared, did you mean "token"?`},
+			{File: "deploy/README.md", Line: 29, Message: `required input "token" is missing`},
+		},
+	})
+	r.Failed("lint/action.yml", "lint/README.md", errors.New("failed to parse lint/action.yml: line 3: mapping values are not allowed"))
+	return r
+}
+
+func TestWriteGitHub(t *testing.T) {
+	// arrange
+	r := githubReport()
+	var b bytes.Buffer
+
+	// act
+	err := r.WriteGitHub(&b)
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, "::warning file=deploy/README.md,line=31,col=7,title=Usage warning::input \"tokn\" is not declared, did you mean \"token\"?\n"+
+		"::warning file=deploy/README.md,line=29,title=Usage warning::required input \"token\" is missing\n"+
+		"::error file=deploy/README.md,line=12,title=README out-of-date::deploy/README.md is out-of-date, run gh action-readme update%0A- inputs: 1 row changed (added `a|b`)%0A- usage: content changed (1 line added)\n"+
+		"::group::deploy/README.md\n"+
+		"--- a/deploy/README.md\n+++ b/deploy/README.md\n@@ -12 +12 @@\n-old\n+new\n"+
+		"::endgroup::\n"+
+		"::error file=lint/README.md,title=README error::failed to parse lint/action.yml: line 3: mapping values are not allowed\n",
+		b.String())
+}
+
+func TestWorkflowCommandEscaping(t *testing.T) {
+	assert.Equal(t, "50%25 done%0Anext", workflowCommandData.Replace("50% done\nnext"))
+	assert.Equal(t, "a%3Ab%2Cc", workflowCommandProperty.Replace("a:b,c"))
+}
+
+func TestAppendStepSummary(t *testing.T) {
+	// arrange
+	path := filepath.Join(t.TempDir(), "summary.md")
+	require.NoError(t, os.WriteFile(path, []byte("# Tests\n\n"), 0644))
+	r := githubReport()
+
+	// act
+	err := r.AppendStepSummary(path)
+
+	// assert
+	require.NoError(t, err)
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "# Tests\n\n"+
+		"### gh action-readme diff\n\n"+
+		"| Action | README | Status | Changed sections |\n"+
+		"|--------|--------|--------|------------------|\n"+
+		"| `build/action.yml` | `build/README.md` | up-to-date |  |\n"+
+		"| `deploy/action.yml` | `deploy/README.md` | out-of-date | `inputs`: 1 row changed (added `a\\|b`)<br>`usage`: content changed (1 line added) |\n"+
+		"| `lint/action.yml` | `lint/README.md` | error | failed to parse lint/action.yml: line 3: mapping values are not allowed |\n"+
+		"\n1 out-of-date, 1 error, 1 up-to-date\n\n",
+		string(content))
+}
+
+func TestAppendStepSummary_MultiLineError(t *testing.T) {
+	// arrange
+	path := filepath.Join(t.TempDir(), "summary.md")
+	r := New("update")
+	r.Failed("lint/action.yml", "lint/README.md", errors.New("invalid lint/action.yml:\n10:7: inputs.mode.default must be one of a|b\n12:3: runs.using must be one of node20\\|composite\n"))
+
+	// act
+	err := r.AppendStepSummary(path)
+
+	// assert
+	require.NoError(t, err)
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(content),
+		"| `lint/action.yml` | `lint/README.md` | error | invalid lint/action.yml:<br>10:7: inputs.mode.default must be one of a\\|b<br>12:3: runs.using must be one of node20\\|composite |\n")
+}
@@ -0,0 +1,212 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/reakaleek/gh-action-readme/internal/markdown"
+	"github.com/urfave/cli/v2"
+)
+
+// Formats of the command output
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+	// FormatGitHub writes workflow commands and a job summary for GitHub Actions
+	FormatGitHub = "github"
+)
+
+// SchemaVersion is the version of the JSON report, it changes if fields are renamed or removed
+const SchemaVersion = 1
+
+// Status of a README after running a command
+type Status string
+
+const (
+	StatusUpToDate  Status = "up-to-date"
+	StatusOutOfDate Status = "out-of-date"
+	StatusUpdated   Status = "updated"
+	StatusCreated   Status = "created"
+	StatusSkipped   Status = "skipped"
+	StatusError     Status = "error"
+)
+
+// Section is a stale placeholder section of a README
+type Section struct {
+	Name string `json:"name"`
+	Line int    `json:"line"`
+	// Detail describes how the section differs
+	// Example: "2 rows changed (added `token`, default of `retries` changed)"
+	Detail string `json:"detail"`
+}
+
+// Entry is the result of a command for the README of an action.
+// Action is empty for a README that only documents actions in other directories.
+type Entry struct {
+	Action string `json:"action"`
+	Readme string `json:"readme"`
+	Status Status `json:"status"`
+	// Line is the first line of the README that differs, 0 if nothing differs
+	Line          int       `json:"line"`
+	StaleSections []Section `json:"staleSections"`
+	Patch         string    `json:"patch"`
+	Warnings      []string  `json:"warnings"`
+	// UsageIssues are the usage issues of the README, they are added to the warnings and
+	// written as warning annotations with their position in GitHub Actions
+	UsageIssues []markdown.UsageIssue `json:"-"`
+	// Error is only written for entries with the error status
+	Error string `json:"error,omitempty"`
+}
+
+// Summary counts the READMEs by status
+type Summary struct {
+	Total     int `json:"total"`
+	UpToDate  int `json:"upToDate"`
+	OutOfDate int `json:"outOfDate"`
+	Updated   int `json:"updated"`
+	Created   int `json:"created"`
+	Skipped   int `json:"skipped"`
+	Error     int `json:"error"`
+}
+
+// Report is the machine-readable result of the diff, update and init commands
+type Report struct {
+	Version int     `json:"version"`
+	Command string  `json:"command"`
+	Actions []Entry `json:"actions"`
+	Summary Summary `json:"summary"`
+}
+
+// New returns an empty report of the given command
+func New(command string) *Report {
+	return &Report{
+		Version: SchemaVersion,
+		Command: command,
+		Actions: []Entry{},
+	}
+}
+
+// Add adds the entry and counts its status in the summary
+func (r *Report) Add(e Entry) {
+	// Empty lists are written as [] instead of null, so consumers don't have to check for both
+	if e.StaleSections == nil {
+		e.StaleSections = []Section{}
+	}
+	if e.Warnings == nil {
+		e.Warnings = warnings(e.UsageIssues)
+	}
+	r.Actions = append(r.Actions, e)
+	r.Summary.Total++
+	switch e.Status {
+	case StatusUpToDate:
+		r.Summary.UpToDate++
+	case StatusOutOfDate:
+		r.Summary.OutOfDate++
+	case StatusUpdated:
+		r.Summary.Updated++
+	case StatusCreated:
+		r.Summary.Created++
+	case StatusSkipped:
+		r.Summary.Skipped++
+	case StatusError:
+		r.Summary.Error++
+	}
+}
+
+// Failed adds an entry with the error status
+func (r *Report) Failed(actionPath string, readmePath string, err error) {
+	r.Add(Entry{
+		Action: actionPath,
+		Readme: readmePath,
+		Status: StatusError,
+		Error:  err.Error(),
+	})
+}
+
+// StaleSections returns the report sections of the stale sections of a README
+func StaleSections(stale []markdown.StaleSection) []Section {
+	sections := make([]Section, len(stale))
+	for i, s := range stale {
+		sections[i] = Section{Name: s.Name, Line: s.Line, Detail: s.Detail}
+	}
+	return sections
+}
+
+// warnings returns the usage issues of a README as warnings
+func warnings(issues []markdown.UsageIssue) []string {
+	warnings := make([]string, len(issues))
+	for i, issue := range issues {
+		warnings[i] = issue.String()
+	}
+	return warnings
+}
+
+// Write writes the report as indented JSON
+func (r *Report) Write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	// Patches are full of markdown and HTML comments, they stay readable without escaping
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(r)
+}
+
+// Emit writes the report to the output file if one is given, otherwise to stdout if the format is json.
+// With the github format, the workflow commands are written to stdout and the job summary is appended
+// to the file in $GITHUB_STEP_SUMMARY, in addition to the output file.
+// With the text format and no output file, the report isn't written at all.
+func (r *Report) Emit(format string, output string) error {
+	if format == FormatGitHub {
+		if err := r.WriteGitHub(os.Stdout); err != nil {
+			return err
+		}
+		if path := os.Getenv("GITHUB_STEP_SUMMARY"); path != "" {
+			if err := r.AppendStepSummary(path); err != nil {
+				return err
+			}
+		}
+	}
+	if output != "" {
+		f, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+		if err := r.Write(f); err != nil {
+			_ = f.Close()
+			return err
+		}
+		return f.Close()
+	}
+	if format == FormatJSON {
+		return r.Write(os.Stdout)
+	}
+	return nil
+}
+
+// Flags returns the --format and --output flags.
+// The text and json formats are supported by all commands, other formats have to be given.
+func Flags(format *string, output *string, extra ...string) []cli.Flag {
+	formats := append([]string{FormatText, FormatJSON}, extra...)
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:        "format",
+			Value:       FormatText,
+			Destination: format,
+			Usage:       fmt.Sprintf("Output format, one of %s", strings.Join(formats, ", ")),
+			Action: func(_ *cli.Context, value string) error {
+				if !slices.Contains(formats, value) {
+					return fmt.Errorf("unknown format %q, expected one of %s", value, strings.Join(formats, ", "))
+				}
+				return nil
+			},
+		},
+		&cli.StringFlag{
+			Name:        "output",
+			Destination: output,
+			Usage:       "Write the JSON report to the given file",
+		},
+	}
+}
@@ -0,0 +1,71 @@
+package report
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/reakaleek/gh-action-readme/internal/markdown"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportSummary(t *testing.T) {
+	// arrange
+	r := New("diff")
+
+	// act
+	r.Add(Entry{Readme: "a/README.md", Status: StatusUpToDate})
+	r.Add(Entry{Readme: "b/README.md", Status: StatusOutOfDate})
+	r.Add(Entry{Readme: "c/README.md", Status: StatusOutOfDate})
+	r.Failed("d/action.yml", "d/README.md", errors.New("failed to parse d/action.yml"))
+
+	// assert
+	assert.Equal(t, Summary{Total: 4, UpToDate: 1, OutOfDate: 2, Error: 1}, r.Summary)
+	assert.Equal(t, "failed to parse d/action.yml", r.Actions[3].Error)
+}
+
+func TestReportWrite(t *testing.T) {
+	// arrange
+	r := New("update")
+	r.Add(Entry{
+		Action:      "action.yml",
+		Readme:      "README.md",
+		Status:      StatusUpToDate,
+		UsageIssues: []markdown.UsageIssue{{File: "README.md", Line: 16, Column: 7, Message: `input "tokn" is not declared`}},
+	})
+	r.Failed("deploy/action.yml", "deploy/README.md", errors.New("failed to parse action file"))
+	var b bytes.Buffer
+
+	// act
+	err := r.Write(&b)
+
+	// assert
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"version": 1,
+		"command": "update",
+		"actions": [
+			{
+				"action": "action.yml",
+				"readme": "README.md",
+				"status": "up-to-date",
+				"line": 0,
+				"staleSections": [],
+				"patch": "",
+				"warnings": ["README.md:16:7: input \"tokn\" is not declared"]
+			},
+			{
+				"action": "deploy/action.yml",
+				"readme": "deploy/README.md",
+				"status": "error",
+				"line": 0,
+				"staleSections": [],
+				"patch": "",
+				"warnings": [],
+				"error": "failed to parse action file"
+			}
+		],
+		"summary": {"total": 2, "upToDate": 1, "outOfDate": 0, "updated": 0, "created": 0, "skipped": 0, "error": 1}
+	}`, b.String())
+}